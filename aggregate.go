@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// footerLabel 汇总行第一个非聚合列中显示的标签
+const footerLabel = "Total"
+
+// AggregateSpec 列聚合规则
+type AggregateSpec struct {
+	Column string // 列名或从 1 开始的列序号
+	Func   string // 聚合函数: sum、avg、count、min、max
+}
+
+// ParseAggregates 解析聚合规则，格式: "cost:sum,latency:avg"
+func ParseAggregates(spec string) ([]AggregateSpec, error) {
+	var specs []AggregateSpec
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		sep := strings.LastIndex(item, ":")
+		if sep <= 0 {
+			return nil, fmt.Errorf("invalid aggregate %q, expected column:function", item)
+		}
+		fn := strings.ToLower(strings.TrimSpace(item[sep+1:]))
+		switch fn {
+		case "sum", "avg", "count", "min", "max":
+		default:
+			return nil, fmt.Errorf("unknown aggregate function %q in %q", fn, item)
+		}
+		specs = append(specs, AggregateSpec{Column: strings.TrimSpace(item[:sep]), Func: fn})
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no aggregates")
+	}
+	return specs, nil
+}
+
+// Aggregate 计算一组单元格的聚合值
+// count 统计非空单元格，其他函数只计算数字单元格，没有数字时返回空字符串
+func Aggregate(fn string, cells []string) string {
+	if fn == "count" {
+		count := 0
+		for _, cell := range cells {
+			if strings.TrimSpace(cell) != "" {
+				count++
+			}
+		}
+		return strconv.Itoa(count)
+	}
+
+	var values []float64
+	decimals := 0
+	for _, cell := range cells {
+		value, ok := parseNumber(cell)
+		if !ok {
+			continue
+		}
+		values = append(values, value)
+		decimals = max(decimals, countDecimals(cell))
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	var result float64
+	switch fn {
+	case "sum", "avg":
+		for _, value := range values {
+			result += value
+		}
+		if fn == "avg" {
+			result /= float64(len(values))
+			decimals = max(decimals, 2)
+		}
+	case "min":
+		result = math.Inf(1)
+		for _, value := range values {
+			result = math.Min(result, value)
+		}
+	case "max":
+		result = math.Inf(-1)
+		for _, value := range values {
+			result = math.Max(result, value)
+		}
+	}
+	return trimNegativeZero(strconv.FormatFloat(result, 'f', decimals, 64))
+}
+
+// countDecimals 返回数字单元格的小数位数
+func countDecimals(cell string) int {
+	cell = strings.TrimSpace(cell)
+	if strings.ContainsAny(cell, "eE") {
+		return 0
+	}
+	if dot := strings.Index(cell, "."); dot >= 0 {
+		return len(cell) - dot - 1
+	}
+	return 0
+}
+
+// AppendFooter 追加加粗的汇总行，汇总行中的数字仍会应用列的数字格式
+func AppendFooter(rows [][]string, spec string) ([][]string, error) {
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	specs, err := ParseAggregates(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	footer := make([]string, len(rows[0]))
+	aggregated := make([]bool, len(rows[0]))
+	for _, aggregate := range specs {
+		index, err := findColumn(rows[0], aggregate.Column)
+		if err != nil {
+			return nil, err
+		}
+		footer[index] = bold(Aggregate(aggregate.Func, columnCells(rows[1:], index)))
+		aggregated[index] = true
+	}
+
+	// 在第一个未聚合的列中显示标签
+	for i := range footer {
+		if !aggregated[i] {
+			footer[i] = bold(footerLabel)
+			break
+		}
+	}
+
+	return append(rows, footer), nil
+}
+
+// bold 将非空文本加粗
+func bold(s string) string {
+	if s == "" {
+		return s
+	}
+	return "**" + s + "**"
+}
+
+// unbold 去掉加粗标记，返回内部文本和是否加粗
+func unbold(s string) (string, bool) {
+	if len(s) > 4 && strings.HasPrefix(s, "**") && strings.HasSuffix(s, "**") {
+		return s[2 : len(s)-2], true
+	}
+	return s, false
+}
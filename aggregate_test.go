@@ -0,0 +1,119 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseAggregates(t *testing.T) {
+	specs, err := ParseAggregates("cost:sum, latency:AVG")
+	if err != nil {
+		t.Fatalf("ParseAggregates() error = %v", err)
+	}
+	expected := []AggregateSpec{{"cost", "sum"}, {"latency", "avg"}}
+	if len(specs) != len(expected) || specs[0] != expected[0] || specs[1] != expected[1] {
+		t.Errorf("ParseAggregates() = %+v, 期望 %+v", specs, expected)
+	}
+
+	for _, spec := range []string{"cost", "cost:median", ""} {
+		if _, err := ParseAggregates(spec); err == nil {
+			t.Errorf("ParseAggregates(%q) 应返回错误", spec)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	cells := []string{"10", "2.5", "", "n/a", "1,000"}
+
+	tests := []struct {
+		fn       string
+		cells    []string
+		expected string
+	}{
+		{"sum", cells, "1012.5"},
+		{"avg", cells, "337.50"},
+		{"count", cells, "4"},
+		{"min", cells, "2.5"},
+		{"max", cells, "1000.0"},
+		{"sum", []string{"0.1", "0.2"}, "0.3"},
+		{"avg", []string{"1", "2"}, "1.50"},
+		{"sum", []string{"a", ""}, ""},
+		{"count", []string{}, "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fn, func(t *testing.T) {
+			result := Aggregate(tt.fn, tt.cells)
+			if result != tt.expected {
+				t.Errorf("Aggregate(%q, %q) = %q, 期望 %q", tt.fn, tt.cells, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAppendFooter(t *testing.T) {
+	rows := [][]string{
+		{"item", "^rcost", "latency"},
+		{"a", "1200", "10"},
+		{"b", "300.5", "15"},
+	}
+
+	result, err := AppendFooter(rows, "cost:sum,latency:max")
+	if err != nil {
+		t.Fatalf("AppendFooter() error = %v", err)
+	}
+	expected := []string{"**Total**", "**1500.5**", "**15**"}
+	if !equalRows(result[len(result)-1:], [][]string{expected}) {
+		t.Errorf("AppendFooter() 汇总行 = %q, 期望 %q", result[len(result)-1], expected)
+	}
+
+	if _, err := AppendFooter(rows, "price:sum"); err == nil {
+		t.Error("未知列名应返回错误")
+	}
+}
+
+func TestConvertToMarkdownWithFooter(t *testing.T) {
+	converter := NewConverter()
+	converter.NumberFormats = map[string]NumberFormat{"cost": {"num", 2}}
+
+	rows, err := AppendFooter([][]string{
+		{"item", "^rcost"},
+		{"a", "1200"},
+		{"b", "300"},
+	}, "cost:sum")
+	if err != nil {
+		t.Fatalf("AppendFooter() error = %v", err)
+	}
+
+	expected := "| item       | cost          |\n" +
+		"|------------|--------------:|\n" +
+		"| a          | 1,200.00      |\n" +
+		"| b          | 300.00        |\n" +
+		"| **Total**  | **1,500.00**  |"
+	result := converter.ConvertToMarkdown(rows)
+	if result != expected {
+		t.Errorf("ConvertToMarkdown() = %q, 期望 %q", result, expected)
+	}
+}
+
+func TestTransformRowsFooterAfterColumns(t *testing.T) {
+	rows := [][]string{{"name", "v"}, {"a", "1"}, {"b", "2"}}
+	result, err := transformRows(rows, options{footer: "v:sum", columns: "v"}, "en")
+	if err != nil {
+		t.Fatalf("transformRows() error = %v", err)
+	}
+	// 只选中聚合列时没有放标签的列，汇总行只有聚合值
+	expected := [][]string{{"v"}, {"1"}, {"2"}, {"**3**"}}
+	if !equalRows(result, expected) {
+		t.Errorf("transformRows() = %q, 期望 %q", result, expected)
+	}
+
+	rows = [][]string{{"name", "x", "v"}, {"a", "9", "1"}, {"b", "9", "2"}}
+	result, err = transformRows(rows, options{footer: "v:sum", columns: "v,name"}, "en")
+	if err != nil {
+		t.Fatalf("transformRows() error = %v", err)
+	}
+	expected = [][]string{{"v", "name"}, {"1", "a"}, {"2", "b"}, {"**3**", "**Total**"}}
+	if !equalRows(result, expected) {
+		t.Errorf("transformRows() = %q, 期望 %q", result, expected)
+	}
+}
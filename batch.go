@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileTable 一个输入文件及其转换后的 Markdown 表格
+type FileTable struct {
+	Path     string
+	Markdown string
+}
+
+// ExpandFileArgs 展开命令行中的文件参数
+// 包含通配符（* ? [）的参数用 filepath.Glob 展开（Windows 的 shell 不会展开通配符），
+// 没有匹配的文件时报错；已存在的文件（例如 shell 展开后的 data[1].csv）按原样使用，不再展开；
+// 重复的文件只保留第一次出现
+func ExpandFileArgs(args []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		matches := []string{arg}
+		if _, statErr := os.Stat(arg); statErr != nil && strings.ContainsAny(arg, "*?[") {
+			var err error
+			matches, err = filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %v", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %q", arg)
+			}
+		}
+		for _, match := range matches {
+			if seen[match] {
+				continue
+			}
+			seen[match] = true
+			files = append(files, match)
+		}
+	}
+	return files, nil
+}
+
+// OutputPaths 计算每个输入文件在 outdir 中对应的 .md 文件路径
+// 不同目录中的同名文件会输出到同一路径，此时报错
+func OutputPaths(files []string, outdir string) ([]string, error) {
+	paths := make([]string, len(files))
+	sources := make(map[string]string)
+	for i, file := range files {
+		base := filepath.Base(file)
+		name := strings.TrimSuffix(base, filepath.Ext(base)) + ".md"
+		path := filepath.Join(outdir, name)
+		if previous, ok := sources[path]; ok {
+			return nil, fmt.Errorf("%s and %s would both be written to %s", previous, file, path)
+		}
+		sources[path] = file
+		paths[i] = path
+	}
+	return paths, nil
+}
+
+// JoinFileTables 将多个文件的表格合并为一个 Markdown 文档，每个表格前加上文件名标题
+// 只有一个文件时直接返回该表格
+func JoinFileTables(tables []FileTable) string {
+	if len(tables) == 1 {
+		return tables[0].Markdown
+	}
+	sections := make([]string, len(tables))
+	for i, table := range tables {
+		sections[i] = "## " + table.Path + "\n\n" + table.Markdown
+	}
+	return strings.Join(sections, "\n\n")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandFileArgs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.csv", "b.csv", "c.tsv", "data[1].txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x,y"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	join := func(names ...string) []string {
+		paths := make([]string, len(names))
+		for i, name := range names {
+			paths[i] = filepath.Join(dir, name)
+		}
+		return paths
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		wantErr  bool
+	}{
+		{"普通文件", join("c.tsv", "a.csv"), join("c.tsv", "a.csv"), false},
+		{"通配符", join("*.csv"), join("a.csv", "b.csv"), false},
+		{"去掉重复文件", join("a.csv", "*.csv"), join("a.csv", "b.csv"), false},
+		{"没有匹配的文件", join("*.xlsx"), nil, true},
+		{"无效的模式", join("[a.csv"), nil, true},
+		{"文件名包含方括号", join("data[1].txt"), join("data[1].txt"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExpandFileArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandFileArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && strings.Join(result, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("ExpandFileArgs() = %v, 期望 %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOutputPaths(t *testing.T) {
+	paths, err := OutputPaths([]string{"data/sales.csv", "report.tsv", "notes"}, "out")
+	if err != nil {
+		t.Fatalf("OutputPaths() error = %v", err)
+	}
+	expected := []string{filepath.Join("out", "sales.md"), filepath.Join("out", "report.md"), filepath.Join("out", "notes.md")}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("OutputPaths() = %v, 期望 %v", paths, expected)
+	}
+
+	if _, err := OutputPaths([]string{"a/data.csv", "b/data.tsv"}, "out"); err == nil {
+		t.Error("OutputPaths() 期望同名文件报错")
+	}
+}
+
+func TestJoinFileTables(t *testing.T) {
+	single := JoinFileTables([]FileTable{{Path: "a.csv", Markdown: "| a |\n|---|"}})
+	if single != "| a |\n|---|" {
+		t.Errorf("单个文件 = %q, 期望只有表格", single)
+	}
+
+	joined := JoinFileTables([]FileTable{
+		{Path: "a.csv", Markdown: "| a |\n|---|"},
+		{Path: "b.tsv", Markdown: "| b |\n|---|"},
+	})
+	expected := "## a.csv\n\n| a |\n|---|\n\n## b.tsv\n\n| b |\n|---|"
+	if joined != expected {
+		t.Errorf("多个文件 = %q, 期望 %q", joined, expected)
+	}
+}
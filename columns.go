@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SelectColumns 按列名或列序号选择并重新排列列
+// spec 格式: "Name,3,5-7"，序号从 1 开始，范围可以倒序（例如 "7-5"）
+func SelectColumns(rows [][]string, spec string) ([][]string, error) {
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	indexes, err := resolveColumns(rows[0], spec)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]string, len(rows))
+	for i, row := range rows {
+		selected := make([]string, len(indexes))
+		for j, index := range indexes {
+			if index < len(row) {
+				selected[j] = row[index]
+			}
+		}
+		result[i] = selected
+	}
+	return result, nil
+}
+
+// RenameColumns 按 "旧列名=新列名" 映射重命名表头，保留表头中的指令
+func RenameColumns(rows [][]string, spec string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		oldName, newName, ok := strings.Cut(item, "=")
+		if !ok {
+			return fmt.Errorf("invalid rename %q, expected old=new", item)
+		}
+		index, err := findColumn(rows[0], strings.TrimSpace(oldName))
+		if err != nil {
+			return err
+		}
+		header := rows[0][index]
+		prefix := header[:len(header)-len(HeaderName(header))]
+		rows[0][index] = prefix + strings.TrimSpace(newName)
+	}
+	return nil
+}
+
+// resolveColumns 将列选择表达式解析为列序号（从 0 开始）
+func resolveColumns(header []string, spec string) ([]int, error) {
+	var indexes []int
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		// 优先按列名匹配，列名可能本身就是数字或包含连字符
+		if index, err := findColumnByName(header, item); err == nil {
+			indexes = append(indexes, index)
+			continue
+		}
+
+		start, end, err := parseColumnRange(item, len(header))
+		if err != nil {
+			return nil, err
+		}
+		step := 1
+		if start > end {
+			step = -1
+		}
+		for i := start; i != end+step; i += step {
+			indexes = append(indexes, i)
+		}
+	}
+
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no columns selected")
+	}
+	return indexes, nil
+}
+
+// parseColumnRange 解析列序号或列序号范围，返回从 0 开始的起止序号
+func parseColumnRange(item string, columnCount int) (int, int, error) {
+	first, last, isRange := strings.Cut(item, "-")
+	start, err := parseColumnIndex(first, columnCount)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unknown column %q", item)
+	}
+	if !isRange {
+		return start, start, nil
+	}
+	end, err := parseColumnIndex(last, columnCount)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unknown column %q", item)
+	}
+	return start, end, nil
+}
+
+// parseColumnIndex 解析从 1 开始的列序号并检查范围
+func parseColumnIndex(s string, columnCount int) (int, error) {
+	index, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if index < 1 || index > columnCount {
+		return 0, fmt.Errorf("column index %d out of range 1-%d", index, columnCount)
+	}
+	return index - 1, nil
+}
+
+// findColumn 按列名或从 1 开始的列序号查找列
+func findColumn(header []string, name string) (int, error) {
+	if index, err := findColumnByName(header, name); err == nil {
+		return index, nil
+	}
+	if index, err := parseColumnIndex(name, len(header)); err == nil {
+		return index, nil
+	}
+	return 0, fmt.Errorf("unknown column %q", name)
+}
+
+// findColumnByName 按列名查找列，忽略表头指令
+func findColumnByName(header []string, name string) (int, error) {
+	for i, cell := range header {
+		if strings.TrimSpace(HeaderName(cell)) == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown column %q", name)
+}
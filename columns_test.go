@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSelectColumns(t *testing.T) {
+	rows := [][]string{
+		{"ID", "^rName", "Age", "City", "Email"},
+		{"1", "Jane", "30", "NYC", "jane@acme.com"},
+		{"2", "John", "25"},
+	}
+
+	tests := []struct {
+		name     string
+		spec     string
+		expected [][]string
+		wantErr  bool
+	}{
+		{
+			"按列名选择",
+			"Name,Email",
+			[][]string{{"^rName", "Email"}, {"Jane", "jane@acme.com"}, {"John", ""}},
+			false,
+		},
+		{
+			"按序号重新排列",
+			"3,1",
+			[][]string{{"Age", "ID"}, {"30", "1"}, {"25", "2"}},
+			false,
+		},
+		{
+			"范围",
+			"2-4",
+			[][]string{{"^rName", "Age", "City"}, {"Jane", "30", "NYC"}, {"John", "25", ""}},
+			false,
+		},
+		{
+			"倒序范围",
+			"3-1",
+			[][]string{{"Age", "^rName", "ID"}, {"30", "Jane", "1"}, {"25", "John", "2"}},
+			false,
+		},
+		{"未知列名", "Phone", nil, true},
+		{"序号越界", "6", nil, true},
+		{"空选择", " , ", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SelectColumns(rows, tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectColumns(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && !equalRows(result, tt.expected) {
+				t.Errorf("SelectColumns(%q) = %v, 期望 %v", tt.spec, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenameColumns(t *testing.T) {
+	rows := [][]string{
+		{"id", "^r:num(2) price", "qty"},
+		{"1", "3.5", "2"},
+	}
+
+	if err := RenameColumns(rows, "price=Unit Price, 3=Quantity"); err != nil {
+		t.Fatalf("RenameColumns() error = %v", err)
+	}
+
+	expected := []string{"id", "^r:num(2) Unit Price", "Quantity"}
+	if !equalRows(rows[:1], [][]string{expected}) {
+		t.Errorf("RenameColumns() 表头 = %v, 期望 %v", rows[0], expected)
+	}
+
+	if err := RenameColumns(rows, "missing=x"); err == nil {
+		t.Error("未知列名应返回错误")
+	}
+	if err := RenameColumns(rows, "id"); err == nil {
+		t.Error("缺少新列名应返回错误")
+	}
+}
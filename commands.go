@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// runFmt implements the fmt subcommand: reformat every table in Markdown files
+func runFmt(args []string, lang string) {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := flags.Bool("w", false, errorMsg(lang, "将结果写回文件而不是输出到标准输出", "Write the result back to the file instead of stdout"))
+	list := flags.Bool("l", false, errorMsg(lang, "列出需要格式化的文件", "List files whose tables need formatting"))
+	setupUsage(flags, lang)
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		if *write || *list {
+			printError(lang, "错误: -w 和 -l 需要文件参数", "Error: -w and -l require file arguments")
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			printErrorf(lang, "读取输入时出错: %v", "Error reading input: %v", err)
+		}
+		fmt.Print(FormatMarkdownTables(string(data)))
+		return
+	}
+
+	files, err := ExpandFileArgs(flags.Args())
+	if err != nil {
+		printErrorf(lang, "错误: %v", "Error: %v", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			printErrorf(lang, "错误: 无法读取文件: %v", "Error: Failed to read file: %v", err)
+		}
+		formatted := FormatMarkdownTables(string(data))
+		changed := formatted != string(data)
+
+		if *list && changed {
+			fmt.Println(file)
+		}
+		if *write && changed {
+			if err := WriteFileAtomic(file, []byte(formatted)); err != nil {
+				printErrorf(lang, "错误: 无法写入文件: %v", "Error: Failed to write file: %v", err)
+			}
+		}
+		if !*write && !*list {
+			fmt.Print(formatted)
+		}
+	}
+}
+
+// runLint implements the lint subcommand: report problems in Markdown tables.
+// Exits with status 1 when any problem is found
+func runLint(args []string, lang string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	jsonOutput := flags.Bool("json", false, errorMsg(lang, "以 JSON 格式输出问题列表", "Print the problems as a JSON report"))
+	setupUsage(flags, lang)
+	flags.Parse(args)
+
+	issues := []LintIssue{}
+	lint := func(name string, data []byte) {
+		lines := strings.Split(normalizeLineEndings(string(data)), "\n")
+		issues = append(issues, LintMarkdown(name, lines)...)
+	}
+	if flags.NArg() == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			printErrorf(lang, "读取输入时出错: %v", "Error reading input: %v", err)
+		}
+		lint("<stdin>", data)
+	} else {
+		files, err := ExpandFileArgs(flags.Args())
+		if err != nil {
+			printErrorf(lang, "错误: %v", "Error: %v", err)
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				printErrorf(lang, "错误: 无法读取文件: %v", "Error: Failed to read file: %v", err)
+			}
+			lint(file, data)
+		}
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(issues); err != nil {
+			printErrorf(lang, "错误: %v", "Error: %v", err)
+		}
+	} else {
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+	}
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runReverse implements the reverse subcommand: convert a Markdown table back to TSV or CSV
+func runReverse(args []string, lang string) {
+	flags := flag.NewFlagSet("reverse", flag.ExitOnError)
+	to := flags.String("to", "tsv", errorMsg(lang, "输出格式: tsv 或 csv", "Output format: tsv or csv"))
+	index := flags.Int("table", 1, errorMsg(lang, "文档包含多个表格时转换第几个（从 1 开始）", "Which table to convert when the document has several (1-based)"))
+	fromClipboard := flags.Bool("clipboard", false, errorMsg(lang, "从剪贴板读取 Markdown，并将结果写回剪贴板", "Read Markdown from the clipboard and write the result back to it"))
+	toClipboard := flags.Bool("copy", false, errorMsg(lang, "将结果写入剪贴板", "Write the result to the clipboard"))
+	setupUsage(flags, lang)
+	flags.Parse(args)
+
+	var input string
+	switch {
+	case flags.NArg() > 1:
+		printError(lang, "错误: reverse 只接受一个文件", "Error: reverse accepts a single file")
+	case flags.NArg() == 1 && *fromClipboard:
+		printError(lang, "错误: -clipboard 不能与文件参数同时使用", "Error: -clipboard cannot be used with file arguments")
+	case flags.NArg() == 1:
+		data, err := os.ReadFile(flags.Arg(0))
+		if err != nil {
+			printErrorf(lang, "错误: 无法读取文件: %v", "Error: Failed to read file: %v", err)
+		}
+		input = decodeInput(data, "utf-8", lang)
+	default:
+		input = readInput(*fromClipboard, clipboardTextTypes, "utf-8", lang)
+	}
+
+	tables := FindMarkdownTables(strings.Split(normalizeLineEndings(input), "\n"))
+	if len(tables) == 0 {
+		printError(lang, "错误: 没有找到 Markdown 表格", "Error: No Markdown table found")
+	}
+	if *index < 1 || *index > len(tables) {
+		printErrorf(lang, "错误: 表格序号 %d 超出范围（共 %d 个表格）", "Error: Table %d is out of range (found %d tables)", *index, len(tables))
+	}
+
+	output, err := ReverseTable(tables[*index-1].Rows, *to)
+	exitOnError(lang, err)
+
+	shouldCopy := *toClipboard || *fromClipboard
+	if !shouldCopy {
+		fmt.Print(output)
+		return
+	}
+	if err := writeToClipboard(output); err != nil {
+		printErrorf(lang, "无法写入剪贴板: %v", "Failed to write to clipboard: %v", err)
+	}
+	successMsg := errorMsg(lang, "✓ 表格已复制到剪贴板", "✓ Table copied to clipboard")
+	fmt.Fprintf(os.Stderr, "%s\n", successMsg)
+}
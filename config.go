@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// projectConfigName 项目配置文件名，从当前目录向上查找
+const projectConfigName = ".excel-to-markdown.toml"
+
+// Config 配置文件内容
+// 顶层的键为所有子命令共用的转换选项，[<子命令>] 中的键只对该子命令生效（例如 serve 的 addr），
+// [profiles.<名称>] 中的键为命名配置，键名与命令行选项相同，例如:
+//
+//	style = "compact"
+//
+//	[serve]
+//	addr = "localhost:9000"
+//
+//	[profiles.report]
+//	sort = "revenue:desc"
+//	max-width = 40
+type Config struct {
+	Options  map[string]string
+	Commands map[string]map[string]string
+	Profiles map[string]map[string]string
+}
+
+// newConfig 返回空配置
+func newConfig() *Config {
+	return &Config{Options: map[string]string{}, Commands: map[string]map[string]string{}, Profiles: map[string]map[string]string{}}
+}
+
+// LoadConfig 读取配置文件，文件不存在时返回空配置
+func LoadConfig(path string) (*Config, error) {
+	config := newConfig()
+
+	var raw map[string]any
+	if _, err := toml.DecodeFile(path, &raw); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return config, nil
+		}
+		return nil, err
+	}
+
+	for key, value := range raw {
+		if key != "profiles" {
+			if table, ok := value.(map[string]any); ok {
+				config.Commands[key] = map[string]string{}
+				for option, value := range table {
+					if err := setConfigValue(config.Commands[key], option, value); err != nil {
+						return nil, fmt.Errorf("%s: [%s]: %v", path, key, err)
+					}
+				}
+				continue
+			}
+			if err := setConfigValue(config.Options, key, value); err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+			continue
+		}
+		profiles, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: profiles must be a table", path)
+		}
+		for name, value := range profiles {
+			settings, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: profile %q must be a table", path, name)
+			}
+			config.Profiles[name] = map[string]string{}
+			for key, value := range settings {
+				if err := setConfigValue(config.Profiles[name], key, value); err != nil {
+					return nil, fmt.Errorf("%s: profile %q: %v", path, name, err)
+				}
+			}
+		}
+	}
+	return config, nil
+}
+
+// setConfigValue 将配置值转换为命令行选项的字符串形式
+// 键名中的下划线视为连字符（max_width 等同于 max-width），字符串数组用逗号连接
+func setConfigValue(settings map[string]string, key string, value any) error {
+	key = strings.ReplaceAll(key, "_", "-")
+	switch v := value.(type) {
+	case string:
+		settings[key] = v
+	case bool:
+		settings[key] = strconv.FormatBool(v)
+	case int64:
+		settings[key] = strconv.FormatInt(v, 10)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("%s: arrays may only contain strings", key)
+			}
+			items[i] = s
+		}
+		settings[key] = strings.Join(items, ",")
+	default:
+		return fmt.Errorf("%s: unsupported value %v", key, value)
+	}
+	return nil
+}
+
+// Merge 合并另一个配置，other 中的值优先
+func (c *Config) Merge(other *Config) {
+	for key, value := range other.Options {
+		c.Options[key] = value
+	}
+	mergeTables(c.Commands, other.Commands)
+	mergeTables(c.Profiles, other.Profiles)
+}
+
+// mergeTables 将 src 中各表的值合并到 dst 中的同名表
+func mergeTables(dst, src map[string]map[string]string) {
+	for name, settings := range src {
+		if dst[name] == nil {
+			dst[name] = map[string]string{}
+		}
+		for key, value := range settings {
+			dst[name][key] = value
+		}
+	}
+}
+
+// Settings 返回子命令最终使用的配置选项，优先级从低到高：顶层选项、[command] 表、指定的命名配置
+// command 或 profile 为空时跳过对应的部分
+func (c *Config) Settings(command, profile string) (map[string]string, error) {
+	settings := make(map[string]string, len(c.Options))
+	for key, value := range c.Options {
+		settings[key] = value
+	}
+	for key, value := range c.Commands[command] {
+		settings[key] = value
+	}
+	if profile == "" {
+		return settings, nil
+	}
+
+	overrides, ok := c.Profiles[profile]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for name := range c.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown profile %q (available: %s)", profile, strings.Join(names, ", "))
+	}
+	for key, value := range overrides {
+		settings[key] = value
+	}
+	return settings, nil
+}
+
+// ConfigPaths 返回配置文件路径，按优先级从低到高排列：
+// 用户配置 $XDG_CONFIG_HOME/excel-to-markdown/config.toml（未设置时使用系统的用户配置目录），
+// 以及从 dir 向上查找到的第一个项目配置 .excel-to-markdown.toml
+func ConfigPaths(dir string) []string {
+	var paths []string
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome, _ = os.UserConfigDir()
+	}
+	if configHome != "" {
+		paths = append(paths, filepath.Join(configHome, "excel-to-markdown", "config.toml"))
+	}
+
+	for {
+		path := filepath.Join(dir, projectConfigName)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return paths
+}
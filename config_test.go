@@ -0,0 +1,180 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig 在 dir 中写入配置文件并返回路径
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "config.toml", `
+style = "compact"
+max_width = 40
+wrap = true
+columns = ["Name", "3", "5-7"]
+
+[serve]
+addr = "localhost:9000"
+
+[profiles.report]
+sort = "revenue:desc"
+footer = "revenue:sum"
+
+[profiles.wiki]
+style = "padded"
+`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	expectedOptions := map[string]string{"style": "compact", "max-width": "40", "wrap": "true", "columns": "Name,3,5-7"}
+	if !reflect.DeepEqual(config.Options, expectedOptions) {
+		t.Errorf("Options = %v, 期望 %v", config.Options, expectedOptions)
+	}
+	expectedCommands := map[string]map[string]string{"serve": {"addr": "localhost:9000"}}
+	if !reflect.DeepEqual(config.Commands, expectedCommands) {
+		t.Errorf("Commands = %v, 期望 %v", config.Commands, expectedCommands)
+	}
+	expectedProfiles := map[string]map[string]string{
+		"report": {"sort": "revenue:desc", "footer": "revenue:sum"},
+		"wiki":   {"style": "padded"},
+	}
+	if !reflect.DeepEqual(config.Profiles, expectedProfiles) {
+		t.Errorf("Profiles = %v, 期望 %v", config.Profiles, expectedProfiles)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"语法错误", "style = "},
+		{"不支持的值", "max-width = 1.5"},
+		{"数组中有非字符串", "columns = [1, 2]"},
+		{"profiles 不是表", "profiles = \"report\""},
+		{"profile 不是表", "[profiles]\nreport = \"x\""},
+		{"命令表中有嵌套表", "[serve.tls]\ncert = \"x\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, t.TempDir(), "config.toml", tt.content)
+			if _, err := LoadConfig(path); err == nil {
+				t.Error("LoadConfig() 期望返回错误")
+			}
+		})
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	config, err := LoadConfig(filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(config.Options) != 0 || len(config.Commands) != 0 || len(config.Profiles) != 0 {
+		t.Errorf("LoadConfig() = %v, 期望空配置", config)
+	}
+}
+
+func TestConfigSettings(t *testing.T) {
+	user := &Config{
+		Options:  map[string]string{"style": "compact", "wrap": "true"},
+		Commands: map[string]map[string]string{"serve": {"addr": "localhost:9000", "sort": "s"}},
+		Profiles: map[string]map[string]string{"report": {"sort": "a", "footer": "b:sum"}},
+	}
+	project := &Config{
+		Options:  map[string]string{"style": "padded"},
+		Commands: map[string]map[string]string{"watch": {"interval": "1s"}},
+		Profiles: map[string]map[string]string{"report": {"sort": "c:desc"}, "wiki": {}},
+	}
+	user.Merge(project)
+
+	tests := []struct {
+		name     string
+		command  string
+		profile  string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{"只有顶层选项", "convert", "", map[string]string{"style": "padded", "wrap": "true"}, false},
+		{
+			"项目配置覆盖用户配置",
+			"convert",
+			"report",
+			map[string]string{"style": "padded", "wrap": "true", "sort": "c:desc", "footer": "b:sum"},
+			false,
+		},
+		{"命令表", "serve", "", map[string]string{"style": "padded", "wrap": "true", "addr": "localhost:9000", "sort": "s"}, false},
+		{"合并的命令表", "watch", "", map[string]string{"style": "padded", "wrap": "true", "interval": "1s"}, false},
+		{
+			"命名配置优先于命令表",
+			"serve",
+			"report",
+			map[string]string{"style": "padded", "wrap": "true", "addr": "localhost:9000", "sort": "c:desc", "footer": "b:sum"},
+			false,
+		},
+		{"空 profile", "convert", "wiki", map[string]string{"style": "padded", "wrap": "true"}, false},
+		{"未知 profile", "convert", "missing", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := user.Settings(tt.command, tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Settings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), "report, wiki") {
+					t.Errorf("Settings() error = %v, 期望列出可用的 profile", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Settings() = %v, 期望 %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConfigPaths(t *testing.T) {
+	root := t.TempDir()
+	configHome := filepath.Join(root, "config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	userPath := filepath.Join(configHome, "excel-to-markdown", "config.toml")
+
+	project := filepath.Join(root, "project")
+	nested := filepath.Join(project, "docs", "tables")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	paths := ConfigPaths(nested)
+	if !reflect.DeepEqual(paths, []string{userPath}) {
+		t.Errorf("没有项目配置时 ConfigPaths() = %v, 期望 %v", paths, []string{userPath})
+	}
+
+	projectPath := writeConfig(t, project, projectConfigName, "style = \"compact\"")
+	writeConfig(t, root, projectConfigName, "style = \"padded\"")
+	paths = ConfigPaths(nested)
+	if !reflect.DeepEqual(paths, []string{userPath, projectPath}) {
+		t.Errorf("ConfigPaths() = %v, 期望 %v", paths, []string{userPath, projectPath})
+	}
+}
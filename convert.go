@@ -0,0 +1,595 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// options holds the command-line options that control conversion
+type options struct {
+	fromClipboard bool
+	toClipboard   bool
+	html          bool
+
+	numberFormat string
+	columns      string
+	rename       string
+	where        string
+	sort         string
+	footer       string
+	groupBy      string
+	agg          string
+	pivot        string
+
+	transpose       bool
+	transposeHeader string
+
+	noHeader     bool
+	detectHeader bool
+	headerStyle  string
+
+	overflow string
+	strict   bool
+
+	maxWidth     int
+	columnWidths string
+	wrap         bool
+
+	style    string
+	previous string
+
+	ambiguousWide bool
+	encoding      string
+	nfc           bool
+
+	outdir string
+	output string
+	append bool
+	update string
+	check  bool
+
+	profile string
+}
+
+// newConvertFlags creates a flag set with every conversion option and bilingual descriptions
+func newConvertFlags(name string, lang string) (*flag.FlagSet, *options) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	opts := &options{}
+
+	// Set flag descriptions based on language
+	clipboardDesc := errorMsg(lang, "从剪贴板读取数据（跨平台支持）", "Read data from clipboard (cross-platform support)")
+	copyDesc := errorMsg(lang, "将结果写入剪贴板（跨平台支持）", "Write result to clipboard (cross-platform support)")
+	htmlDesc := errorMsg(lang,
+		"从剪贴板读取时优先使用 HTML 格式，保留单元格内换行（Linux 的 wl-paste 和 xclip 支持）",
+		"Prefer the clipboard's HTML flavor when reading, keeping line breaks inside cells (wl-paste and xclip on Linux)")
+	formatDesc := errorMsg(lang,
+		"按列设置数字格式，例如 \"价格:num(2),比例:pct(1)\"（num 千分位, fixed 固定小数, pct 百分比）",
+		"Per-column number format, e.g. \"Price:num(2),Rate:pct(1)\" (num thousands, fixed decimals, pct percent)")
+	columnsDesc := errorMsg(lang,
+		"按列名或序号选择并排列列，支持范围，例如 \"Name,3,5-7\"",
+		"Select and reorder columns by name or index, ranges allowed, e.g. \"Name,3,5-7\"")
+	renameDesc := errorMsg(lang,
+		"重命名列，例如 \"old=new,旧列名=新列名\"",
+		"Rename columns, e.g. \"old=new,qty=Quantity\"")
+	whereDesc := errorMsg(lang,
+		"按条件过滤数据行，例如 'status == \"open\" && priority <= 2'",
+		"Filter data rows by expression, e.g. 'status == \"open\" && priority <= 2'")
+	sortDesc := errorMsg(lang,
+		"按一列或多列排序数据行，例如 \"priority:asc,due:desc\"",
+		"Sort data rows by one or more columns, e.g. \"priority:asc,due:desc\"")
+	transposeDesc := errorMsg(lang, "行列转置（适合纵向排列的键值表）", "Swap rows and columns (useful for vertical key/value sheets)")
+	transposeHeaderDesc := errorMsg(lang,
+		"转置时作为新表头行的列（列名或序号，默认第一列）",
+		"Column (name or index) that becomes the header row when transposing (default: first column)")
+	footerDesc := errorMsg(lang,
+		"追加加粗的汇总行，例如 \"cost:sum,latency:avg\"（sum、avg、count、min、max）",
+		"Append a bold summary row, e.g. \"cost:sum,latency:avg\" (sum, avg, count, min, max)")
+	groupByDesc := errorMsg(lang, "按一列或多列分组，例如 \"region,product\"", "Group rows by one or more columns, e.g. \"region,product\"")
+	aggDesc := errorMsg(lang,
+		"分组时计算的聚合值，例如 \"cost:sum,id:count\"（默认统计行数）",
+		"Aggregates computed per group, e.g. \"cost:sum,id:count\" (default: row count)")
+	pivotDesc := errorMsg(lang,
+		"将长表透视为宽表，例如 \"row=region,col=quarter,value=revenue,agg=sum\"",
+		"Pivot a long table into a wide one, e.g. \"row=region,col=quarter,value=revenue,agg=sum\"")
+	noHeaderDesc := errorMsg(lang, "输入没有表头，自动生成表头", "Input has no header row, generate one")
+	detectHeaderDesc := errorMsg(lang, "自动判断第一行是否为表头，不是时生成表头", "Guess whether the first row is a header and generate one if not")
+	headerStyleDesc := errorMsg(lang,
+		"生成表头的样式: column（Column 1..N）或 letter（A, B, C）",
+		"Generated header style: column (Column 1..N) or letter (A, B, C)")
+	overflowDesc := errorMsg(lang,
+		"单元格多于表头时的处理方式: extend（扩展表头）、merge（合并到最后一列）、truncate（丢弃）",
+		"How to handle rows with more cells than the header: extend (extend header), merge (into last column), truncate (drop)")
+	strictDesc := errorMsg(lang, "行的单元格数与表头不一致时报错退出", "Fail when a row's cell count differs from the header")
+	maxWidthDesc := errorMsg(lang, "所有列的最大显示宽度（0 表示不限制）", "Maximum display width for all columns (0 means unlimited)")
+	colWidthDesc := errorMsg(lang, "按列设置最大显示宽度，例如 \"描述:40,备注:20\"", "Per-column maximum display width, e.g. \"Description:40,Notes:20\"")
+	wrapDesc := errorMsg(lang, "超宽单元格用 <br> 换行而不是截断", "Wrap over-wide cells with <br> instead of truncating them")
+	styleDesc := errorMsg(lang,
+		"输出样式: padded（按列宽填充）、compact（不填充）、stable（沿用 -previous 的列宽，减少 diff）",
+		"Output style: padded (aligned columns), compact (no padding), stable (keep column widths from -previous to minimize diffs)")
+	previousDesc := errorMsg(lang, "stable 样式使用的上一版本 Markdown 文件", "Previous version of the Markdown output used by the stable style")
+	ambiguousWideDesc := errorMsg(lang,
+		"将歧义宽度字符（如 ①、○、希腊字母）按全角计算，适用于中日韩终端",
+		"Treat East Asian ambiguous-width characters (e.g. ①, ○, Greek letters) as wide, for CJK terminals")
+	encodingDesc := errorMsg(lang,
+		"输入编码，例如 utf-16le、gbk、shift_jis、windows-1252（默认自动检测）",
+		"Input encoding, e.g. utf-16le, gbk, shift_jis, windows-1252 (default: auto-detect)")
+	outdirDesc := errorMsg(lang,
+		"为每个输入文件在该目录中生成单独的 .md 文件",
+		"Write one .md file per input file into this directory")
+	outputDesc := errorMsg(lang, "将结果写入文件（转换成功后原子替换）", "Write the result to a file (atomically replaced after a successful conversion)")
+	appendDesc := errorMsg(lang, "追加到 -o 指定的文件末尾而不是替换", "Append to the -o file instead of replacing it")
+	updateDesc := errorMsg(lang,
+		"更新 Markdown 文件中 <!-- table:src=文件 --> 与 <!-- /table --> 之间的表格",
+		"Regenerate the tables between <!-- table:src=file --> and <!-- /table --> markers in a Markdown file")
+	checkDesc := errorMsg(lang,
+		"与 -update 一起使用：不写入文件，表格过期时输出差异并以非零状态退出（适用于 CI）",
+		"With -update: don't write the file, print a diff and exit non-zero if any table is stale (for CI)")
+	profileDesc := errorMsg(lang,
+		"使用配置文件中 [profiles.<名称>] 定义的选项",
+		"Use the options of [profiles.<name>] from the config file")
+	nfcDesc := errorMsg(lang,
+		"对单元格进行 Unicode NFC 规范化（合并分解形式的重音字符）",
+		"Apply Unicode NFC normalization to cells (compose decomposed accented characters)")
+
+	flags.BoolVar(&opts.fromClipboard, "clipboard", false, clipboardDesc)
+	flags.BoolVar(&opts.toClipboard, "copy", false, copyDesc)
+	flags.BoolVar(&opts.html, "html", false, htmlDesc)
+	flags.StringVar(&opts.numberFormat, "format", "", formatDesc)
+	flags.StringVar(&opts.columns, "columns", "", columnsDesc)
+	flags.StringVar(&opts.rename, "rename", "", renameDesc)
+	flags.StringVar(&opts.where, "where", "", whereDesc)
+	flags.StringVar(&opts.sort, "sort", "", sortDesc)
+	flags.StringVar(&opts.footer, "footer", "", footerDesc)
+	flags.StringVar(&opts.groupBy, "group-by", "", groupByDesc)
+	flags.StringVar(&opts.agg, "agg", "", aggDesc)
+	flags.StringVar(&opts.pivot, "pivot", "", pivotDesc)
+	flags.BoolVar(&opts.noHeader, "no-header", false, noHeaderDesc)
+	flags.BoolVar(&opts.detectHeader, "detect-header", false, detectHeaderDesc)
+	flags.StringVar(&opts.headerStyle, "header-style", "column", headerStyleDesc)
+	flags.StringVar(&opts.style, "style", "padded", styleDesc)
+	flags.StringVar(&opts.previous, "previous", "", previousDesc)
+	flags.StringVar(&opts.encoding, "encoding", "auto", encodingDesc)
+	flags.BoolVar(&opts.nfc, "nfc", false, nfcDesc)
+	flags.StringVar(&opts.outdir, "outdir", "", outdirDesc)
+	flags.StringVar(&opts.output, "o", "", outputDesc)
+	flags.BoolVar(&opts.append, "append", false, appendDesc)
+	flags.StringVar(&opts.update, "update", "", updateDesc)
+	flags.BoolVar(&opts.check, "check", false, checkDesc)
+	flags.BoolVar(&opts.ambiguousWide, "ambiguous-wide", false, ambiguousWideDesc)
+	flags.IntVar(&opts.maxWidth, "max-width", 0, maxWidthDesc)
+	flags.StringVar(&opts.columnWidths, "col-width", "", colWidthDesc)
+	flags.BoolVar(&opts.wrap, "wrap", false, wrapDesc)
+	flags.StringVar(&opts.overflow, "overflow", "extend", overflowDesc)
+	flags.BoolVar(&opts.strict, "strict", false, strictDesc)
+	flags.BoolVar(&opts.transpose, "transpose", false, transposeDesc)
+	flags.StringVar(&opts.transposeHeader, "transpose-header", "", transposeHeaderDesc)
+	flags.StringVar(&opts.profile, "profile", "", profileDesc)
+	return flags, opts
+}
+
+// applyConfig sets the options from the user and project config files that were not
+// given on the command line. Precedence, from highest to lowest: command-line flags,
+// the selected profile, the [command] table named after the subcommand, top-level config
+// options; at each config level the project config overrides the user config.
+// Top-level options and profiles may only contain conversion options, because every
+// subcommand reads them. Keys listed in skip are ignored
+func applyConfig(flags *flag.FlagSet, profile string, skip map[string]bool, lang string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	config := newConfig()
+	for _, path := range ConfigPaths(dir) {
+		loaded, err := LoadConfig(path)
+		if err != nil {
+			return errorf(lang, "配置文件无效: %v", "Invalid config file: %v", err)
+		}
+		config.Merge(loaded)
+	}
+
+	for name := range config.Commands {
+		if _, ok := findCommand(name); !ok {
+			return errorf(lang, "配置文件中有未知命令的表: [%s]", "Unknown command table in config file: [%s]", name)
+		}
+	}
+	shared, err := config.Settings("", profile)
+	if err != nil {
+		return err
+	}
+	conversion, _ := newConvertFlags(flags.Name(), lang)
+	for key := range shared {
+		if key == "profile" || conversion.Lookup(key) == nil {
+			return errorf(lang,
+				"配置文件中有未知选项: %s（只属于某个命令的选项请写在以命令命名的表中，例如 [serve]）",
+				"Unknown option in config file: %s (options of a single command go in a table named after it, e.g. [serve])", key)
+		}
+	}
+	for key := range config.Commands[flags.Name()] {
+		if key == "profile" || flags.Lookup(key) == nil {
+			return errorf(lang, "配置文件的 [%s] 中有未知选项: %s", "Unknown option in [%s] of config file: %s", flags.Name(), key)
+		}
+	}
+
+	settings, err := config.Settings(flags.Name(), profile)
+	if err != nil {
+		return err
+	}
+	explicit := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if explicit[key] || skip[key] {
+			continue
+		}
+		if err := flags.Set(key, settings[key]); err != nil {
+			return errorf(lang, "配置文件中的选项 %s 无效: %v", "Invalid value for %s in config file: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// validateOptions checks option values that the flag package cannot validate
+func validateOptions(opts options, lang string) error {
+	if opts.maxWidth < 0 {
+		return errorf(lang, "无效的最大宽度: %d", "Invalid maximum width: %d", opts.maxWidth)
+	}
+	switch opts.style {
+	case "padded", "compact", "stable":
+	default:
+		return errorf(lang, "无效的输出样式: %s", "Invalid output style: %s", opts.style)
+	}
+	if opts.headerStyle != "column" && opts.headerStyle != "letter" {
+		return errorf(lang, "无效的表头样式: %s", "Invalid header style: %s", opts.headerStyle)
+	}
+	if opts.groupBy != "" && opts.pivot != "" {
+		return errorf(lang, "-group-by 和 -pivot 不能同时使用", "-group-by and -pivot cannot be used together")
+	}
+	if opts.html && !opts.fromClipboard {
+		return errorf(lang, "-html 需要 -clipboard", "-html requires -clipboard")
+	}
+	return nil
+}
+
+// runConvert implements the convert subcommand, which is also the default
+// when no subcommand is given
+func runConvert(args []string, lang string) {
+	flags, opts := newConvertFlags("convert", lang)
+	setupUsage(flags, lang)
+	flags.Parse(args)
+
+	exitOnError(lang, applyConfig(flags, opts.profile, nil, lang))
+	exitOnError(lang, validateOptions(*opts, lang))
+	if opts.append && opts.output == "" {
+		printError(lang, "错误: -append 需要 -o", "Error: -append requires -o")
+	}
+	if opts.output != "" && opts.outdir != "" {
+		printError(lang, "错误: -o 和 -outdir 不能同时使用", "Error: -o and -outdir cannot be used together")
+	}
+
+	if opts.check && opts.update == "" {
+		printError(lang, "错误: -check 需要 -update", "Error: -check requires -update")
+	}
+	if opts.update != "" {
+		if flags.NArg() > 0 || opts.fromClipboard || opts.toClipboard || opts.output != "" || opts.outdir != "" {
+			printError(lang,
+				"错误: -update 不能与文件参数、-clipboard、-copy、-o 或 -outdir 同时使用",
+				"Error: -update cannot be combined with file arguments, -clipboard, -copy, -o or -outdir")
+		}
+		updateDocument(opts.update, *opts, lang)
+		return
+	}
+
+	var markdown string
+	if flags.NArg() > 0 {
+		// Convert the files given as arguments
+		if opts.fromClipboard {
+			printError(lang, "错误: -clipboard 不能与文件参数同时使用", "Error: -clipboard cannot be used with file arguments")
+		}
+		files, err := ExpandFileArgs(flags.Args())
+		exitOnError(lang, err)
+		markdown = convertFiles(files, *opts, lang)
+		if opts.outdir != "" {
+			return
+		}
+	} else {
+		if opts.outdir != "" {
+			printError(lang, "错误: -outdir 需要文件参数", "Error: -outdir requires file arguments")
+		}
+
+		// Read and validate input
+		input := readInput(opts.fromClipboard, clipboardTypes(opts.html), opts.encoding, lang)
+		validateInput(input, lang)
+
+		// Convert table to markdown
+		var err error
+		markdown, err = convertTable(input, *opts, lang)
+		exitOnError(lang, err)
+	}
+
+	// Output result
+	shouldCopy := opts.toClipboard || opts.fromClipboard
+	if opts.output != "" {
+		writeOutputFile(markdown, *opts, lang)
+		if !shouldCopy {
+			return
+		}
+	}
+	outputResult(markdown, shouldCopy, opts.fromClipboard, lang)
+}
+
+// convertTable converts input table data to markdown
+func convertTable(input string, opts options, lang string) (string, error) {
+	if !(opts.html && isHTMLTable(input)) && !looksLikeTable(input) {
+		return "", errorf(lang, "输入数据不是表格格式", "Input data is not in table format")
+	}
+
+	converter := NewConverter()
+	if opts.numberFormat != "" {
+		formats, err := ParseNumberFormats(opts.numberFormat)
+		if err != nil {
+			return "", errorf(lang, "数字格式无效: %v", "Invalid number format: %v", err)
+		}
+		converter.NumberFormats = formats
+	}
+	if opts.columnWidths != "" {
+		widths, err := ParseColumnWidths(opts.columnWidths)
+		if err != nil {
+			return "", errorf(lang, "列宽无效: %v", "Invalid column width: %v", err)
+		}
+		converter.ColumnMaxWidths = widths
+	}
+	converter.MaxWidth = opts.maxWidth
+	converter.Wrap = opts.wrap
+	converter.Style = opts.style
+	converter.AmbiguousWide = opts.ambiguousWide
+	converter.NFC = opts.nfc
+	converter.HTML = opts.html
+	if opts.previous != "" {
+		previous, err := readPreviousTable(opts.previous, lang)
+		if err != nil {
+			return "", err
+		}
+		converter.Previous = previous
+	}
+
+	rows, err := converter.ParseTable(input)
+	if err != nil {
+		return "", errorf(lang, "解析表格数据失败: %v", "Failed to parse table data: %v", err)
+	}
+	if len(rows) == 0 {
+		return "", errorf(lang, "无法解析表格数据", "Unable to parse table data")
+	}
+
+	if rows, err = normalizeRows(rows, converter.LineNumbers(), opts, lang); err != nil {
+		return "", err
+	}
+	if rows, err = transformRows(rows, opts, lang); err != nil {
+		return "", err
+	}
+	return converter.ConvertToMarkdown(rows), nil
+}
+
+// convertFile converts a single input file to markdown
+func convertFile(path string, opts options, lang string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errorf(lang, "无法读取文件: %v", "Failed to read file: %v", err)
+	}
+	input, err := DecodeInput(data, opts.encoding)
+	if err != nil {
+		return "", errorf(lang, "无法转换 %s 的编码: %v", "Failed to decode %s: %v", path, err)
+	}
+	input = strings.TrimRight(input, "\r\n")
+	if strings.TrimSpace(input) == "" {
+		return "", errorf(lang, "%s 中没有输入数据", "No input data in %s", path)
+	}
+	if !looksLikeTable(input) {
+		return "", errorf(lang, "%s 不是表格格式", "%s is not in table format", path)
+	}
+	return convertTable(input, opts, lang)
+}
+
+// convertFiles converts each input file separately, detecting its format and encoding.
+// With an output directory every table is written to its own .md file and an empty string
+// is returned; otherwise the tables are joined into one document with a heading per file
+func convertFiles(files []string, opts options, lang string) string {
+	tables := make([]FileTable, len(files))
+	for i, file := range files {
+		markdown, err := convertFile(file, opts, lang)
+		exitOnError(lang, err)
+		tables[i] = FileTable{Path: file, Markdown: markdown}
+	}
+
+	if opts.outdir == "" {
+		return JoinFileTables(tables)
+	}
+
+	paths, err := OutputPaths(files, opts.outdir)
+	exitOnError(lang, err)
+	if err := os.MkdirAll(opts.outdir, 0o755); err != nil {
+		printErrorf(lang, "错误: 无法创建输出目录: %v", "Error: Failed to create output directory: %v", err)
+	}
+	for i, table := range tables {
+		if err := WriteFileAtomic(paths[i], []byte(table.Markdown+"\n")); err != nil {
+			printErrorf(lang, "错误: 无法写入文件: %v", "Error: Failed to write file: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "✓ %s → %s\n", table.Path, paths[i])
+	}
+	return ""
+}
+
+// updateDocument regenerates the tables between <!-- table:src=... --> and <!-- /table -->
+// markers in a Markdown file. Sources are resolved relative to the document.
+// With -check the file is left untouched; a unified diff is printed and the
+// program exits non-zero when any table is stale
+func updateDocument(path string, opts options, lang string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		printErrorf(lang, "错误: 无法读取文件: %v", "Error: Failed to read file: %v", err)
+	}
+
+	doc, updates, err := UpdateMarkedTables(string(data), func(source string) (string, error) {
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(filepath.Dir(path), source)
+		}
+		return convertFile(source, opts, lang)
+	})
+	if err != nil {
+		printErrorf(lang, "错误: %s: %v", "Error: %s: %v", path, err)
+	}
+	count := len(updates)
+	if count == 0 {
+		printErrorf(lang, "错误: %s 中没有表格标记", "Error: No table markers found in %s", path)
+	}
+
+	if opts.check {
+		// Only the regenerated regions decide staleness, so line endings elsewhere never fail the check
+		var regions []DiffRegion
+		for _, update := range updates {
+			if update.Stale() {
+				regions = append(regions, DiffRegion{Start: update.StartLine + 1, Old: update.Old, New: update.New})
+			}
+		}
+		if len(regions) > 0 {
+			_, lines := splitLinesKeepEnds(string(data))
+			if lines[len(lines)-1] == "" {
+				lines = lines[:len(lines)-1] // the document ends with a line break
+			}
+			fmt.Print(UnifiedRegionDiff("a/"+path, "b/"+path, lines, regions))
+			printErrorf(lang, "✗ %s 中的表格需要重新生成（使用 -update 更新）",
+				"✗ Tables in %s are out of date (run -update to regenerate them)", path)
+		}
+		successMsg := errorMsg(lang, "✓ %s 中的 %d 个表格都是最新的", "✓ %[2]d table(s) in %[1]s are up to date")
+		fmt.Fprintf(os.Stderr, successMsg+"\n", path, count)
+		return
+	}
+
+	if doc != string(data) {
+		if err := WriteFileAtomic(path, []byte(doc)); err != nil {
+			printErrorf(lang, "错误: 无法写入文件: %v", "Error: Failed to write file: %v", err)
+		}
+	}
+	successMsg := errorMsg(lang, "✓ 已更新 %[2]s 中的 %[1]d 个表格", "✓ Updated %d table(s) in %s")
+	fmt.Fprintf(os.Stderr, successMsg+"\n", count, path)
+}
+
+// readPreviousTable reads the first Markdown table from a previous version of the output
+func readPreviousTable(path string, lang string) ([][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errorf(lang, "无法读取上一版本文件: %v", "Failed to read previous file: %v", err)
+	}
+	tables := FindMarkdownTables(strings.Split(normalizeLineEndings(string(data)), "\n"))
+	if len(tables) == 0 {
+		return nil, errorf(lang, "%s 中没有找到表格", "No table found in %s", path)
+	}
+	return tables[0].Rows, nil
+}
+
+// normalizeRows adds a generated header when needed and pads or trims rows to the
+// header width, reporting each malformed row as a warning (or an error with -strict)
+func normalizeRows(rows [][]string, lines []int, opts options, lang string) ([][]string, error) {
+	if opts.noHeader || (opts.detectHeader && !DetectHeader(rows)) {
+		rows = AddSyntheticHeader(rows, opts.headerStyle)
+		lines = append([]int{0}, lines...)
+	}
+
+	rows, issues, err := NormalizeRows(rows, lines, opts.overflow)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		zh, en := "第 %d 行有 %d 个单元格，期望 %d 个", "line %d has %d cells, expected %d"
+		args := []interface{}{issue.Line, issue.Cells, issue.Expected}
+		if issue.Line == 0 {
+			// The line is unknown, e.g. for HTML input
+			zh, en = "某行有 %d 个单元格，期望 %d 个", "row has %d cells, expected %d"
+			args = args[1:]
+		}
+		if opts.strict {
+			return nil, errorf(lang, zh, en, args...)
+		}
+		fmt.Fprintf(os.Stderr, errorMsg(lang, "警告: "+zh, "Warning: "+en)+"\n", args...)
+	}
+	return rows, nil
+}
+
+// transformRows applies transposing, row filtering, grouping or pivoting, sorting,
+// column selection, footer aggregates and renaming between parsing and rendering.
+// The footer comes after column selection so that its label lands in a selected column
+func transformRows(rows [][]string, opts options, lang string) ([][]string, error) {
+	var err error
+	if opts.transpose {
+		if rows, err = Transpose(rows, opts.transposeHeader); err != nil {
+			return nil, errorf(lang, "转置失败: %v", "Failed to transpose table: %v", err)
+		}
+	}
+	if opts.where != "" {
+		if rows, err = FilterRows(rows, opts.where); err != nil {
+			return nil, errorf(lang, "过滤条件无效: %v", "Invalid filter: %v", err)
+		}
+	}
+	if opts.groupBy != "" {
+		if rows, err = GroupRows(rows, opts.groupBy, opts.agg); err != nil {
+			return nil, errorf(lang, "分组失败: %v", "Failed to group rows: %v", err)
+		}
+	}
+	if opts.pivot != "" {
+		if rows, err = PivotRows(rows, opts.pivot); err != nil {
+			return nil, errorf(lang, "透视失败: %v", "Failed to pivot table: %v", err)
+		}
+	}
+	if opts.sort != "" {
+		if err := SortRows(rows, opts.sort); err != nil {
+			return nil, errorf(lang, "排序失败: %v", "Failed to sort rows: %v", err)
+		}
+	}
+	if opts.columns != "" {
+		if rows, err = SelectColumns(rows, opts.columns); err != nil {
+			return nil, errorf(lang, "选择列失败: %v", "Failed to select columns: %v", err)
+		}
+	}
+	if opts.footer != "" {
+		if rows, err = AppendFooter(rows, opts.footer); err != nil {
+			return nil, errorf(lang, "汇总行无效: %v", "Invalid footer: %v", err)
+		}
+	}
+	if opts.rename != "" {
+		if err := RenameColumns(rows, opts.rename); err != nil {
+			return nil, errorf(lang, "重命名列失败: %v", "Failed to rename columns: %v", err)
+		}
+	}
+	return rows, nil
+}
+
+// writeOutputFile writes markdown to the -o file, replacing it or appending to it with -append.
+// The file is replaced atomically, so a failed run never leaves it truncated
+func writeOutputFile(markdown string, opts options, lang string) {
+	content := markdown
+	if opts.append {
+		existing, err := os.ReadFile(opts.output)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			printErrorf(lang, "错误: 无法读取输出文件: %v", "Error: Failed to read output file: %v", err)
+		}
+		content = AppendDocument(string(existing), markdown)
+	}
+
+	if err := WriteFileAtomic(opts.output, []byte(content+"\n")); err != nil {
+		printErrorf(lang, "错误: 无法写入输出文件: %v", "Error: Failed to write output file: %v", err)
+	}
+	successMsg := errorMsg(lang, "✓ Markdown 表格已写入 %s", "✓ Markdown table written to %s")
+	fmt.Fprintf(os.Stderr, successMsg+"\n", opts.output)
+}
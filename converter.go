@@ -2,51 +2,104 @@ package main
 
 import (
 	"encoding/csv"
+	"io"
 	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
-)
 
-var (
-	// alignmentRegex matches alignment markers in header: ^l, ^c, ^r
-	alignmentRegex = regexp.MustCompile(`(?i)^(\^[lcr])`)
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/width"
 )
 
 // Converter 表格转换器
-type Converter struct{}
+type Converter struct {
+	// NumberFormats 按列名或列序号（从 1 开始）指定的数字格式
+	NumberFormats map[string]NumberFormat
+	// MaxWidth 所有列的最大显示宽度，0 表示不限制
+	MaxWidth int
+	// ColumnMaxWidths 按列名或列序号（从 1 开始）指定的最大显示宽度
+	ColumnMaxWidths map[string]int
+	// Wrap 超宽单元格用 <br> 换行而不是截断
+	Wrap bool
+	// Style 输出样式："padded"（默认，按列宽填充）、"compact"（不填充）或 "stable"（尽量沿用 Previous 的列宽）
+	Style string
+	// Previous 上一版本的表格（表头和数据行），stable 样式下用于保持列宽
+	Previous [][]string
+	// AmbiguousWide 将东亚歧义宽度字符（例如 ①、○、希腊字母）按 2 个宽度计算，适用于中日韩终端
+	AmbiguousWide bool
+	// NFC 解析后对单元格进行 Unicode NFC 规范化
+	NFC bool
+	// HTML 输入可能是剪贴板的 text/html 内容（-html），包含 <table> 的 HTML 片段按 HTML 表格解析
+	HTML bool
+	// Alignments 每列的对齐方式 "l"、"c"、"r"；设置后不再解析表头指令，表头按原样输出
+	// 用于重新格式化已有的 Markdown 表格
+	Alignments []string
+
+	// lineNumbers 最近一次解析得到的每行数据在输入中的行号（从 1 开始）
+	lineNumbers []int
+}
 
 // NewConverter 创建新的转换器实例
 func NewConverter() *Converter {
 	return &Converter{}
 }
 
-// DisplayWidth 计算字符串的显示宽度（考虑中文字符）
-// 中文字符通常占用 2 个显示宽度，英文字符占用 1 个
+// DisplayWidth 计算字符串的显示宽度
+// 按字素簇（grapheme cluster）计算，每个字素簇的宽度依据 Unicode 东亚宽度（East Asian Width）：
+// 全角和宽字符（中文、日文、韩文、大部分 emoji 等）占 2 个宽度，组合字符和零宽字符占 0 个宽度，
+// 歧义宽度字符在 AmbiguousWide 为 true 时占 2 个宽度，否则占 1 个
 func (c *Converter) DisplayWidth(s string) int {
 	width := 0
-	for _, r := range s {
-		// 判断是否为全角字符（包括中文、日文、韩文等）
-		// 全角字符通常占用 2 个显示宽度
-		if unicode.Is(unicode.Han, r) || // 中文字符
-			unicode.Is(unicode.Hiragana, r) || // 日文平假名
-			unicode.Is(unicode.Katakana, r) || // 日文片假名
-			unicode.Is(unicode.Hangul, r) || // 韩文字符
-			(r >= 0xFF00 && r <= 0xFFEF) { // 全角符号
-			width += 2
-		} else {
-			// 半角字符占用 1 个显示宽度
-			width += 1
+	graphemes := uniseg.NewGraphemes(s)
+	for graphemes.Next() {
+		width += c.clusterWidth(graphemes.Runes())
+	}
+	return width
+}
+
+// clusterWidth 计算单个字素簇的显示宽度
+func (c *Converter) clusterWidth(cluster []rune) int {
+	width := 0
+	regionalIndicators := 0
+	for _, r := range cluster {
+		switch {
+		case r == 0xFE0F: // VARIATION SELECTOR-16，使用 emoji 样式显示
+			return 2
+		case r >= 0x1F1E6 && r <= 0x1F1FF: // 区域指示符，两个组成一面国旗
+			regionalIndicators++
 		}
+		width = max(width, c.runeWidth(r))
+	}
+	if regionalIndicators >= 2 {
+		return 2
 	}
 	return width
 }
 
+// runeWidth 计算单个字符的显示宽度
+func (c *Converter) runeWidth(r rune) int {
+	// 控制字符、组合字符和格式字符（零宽空格、零宽连接符等）不占宽度
+	if r == 0 || unicode.IsControl(r) || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	case width.EastAsianAmbiguous:
+		if c.AmbiguousWide {
+			return 2
+		}
+	}
+	return 1
+}
+
 // ColumnWidth 计算指定列的最大显示宽度
 func (c *Converter) ColumnWidth(rows [][]string, columnIndex int) int {
 	maxWidth := 0
 	for _, row := range rows {
 		if columnIndex < len(row) {
-			cellWidth := c.DisplayWidth(row[columnIndex])
+			cellWidth := c.cellWidth(row[columnIndex])
 			if cellWidth > maxWidth {
 				maxWidth = cellWidth
 			}
@@ -55,8 +108,12 @@ func (c *Converter) ColumnWidth(rows [][]string, columnIndex int) int {
 	return maxWidth
 }
 
-// DetectFormat 检测输入格式是 CSV、TSV 还是 Column（空格对齐）
+// DetectFormat 检测输入格式是 CSV、TSV 还是 Column（空格对齐），HTML 为 true 时还会识别 HTML 表格
 func (c *Converter) DetectFormat(data string) string {
+	// 从浏览器或 Excel 复制的 HTML 表格（剪贴板的 text/html 内容）
+	if c.HTML && isHTMLTable(data) {
+		return "html"
+	}
 	// 检查是否包含引号（CSV 的特征）
 	hasQuotes := strings.Contains(data, `"`)
 	// 检查是否包含逗号
@@ -153,17 +210,30 @@ func (c *Converter) ParseCSV(data string) ([][]string, error) {
 	// 使用逗号作为分隔符
 	reader.Comma = ','
 
-	rows, err := reader.ReadAll()
-	if err != nil {
-		return nil, err
+	var rows [][]string
+	c.lineNumbers = nil
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// 过滤空行
+		if isEmptyRow(row) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, row)
+		c.lineNumbers = append(c.lineNumbers, line)
 	}
-
-	// 过滤空行
-	return c.filterEmptyRows(rows), nil
+	return rows, nil
 }
 
 // ParseTSV 解析 TSV 格式的表格数据
 func (c *Converter) ParseTSV(data string) [][]string {
+	offset := leadingLineCount(data)
 	data = strings.TrimSpace(data)
 	// 处理各种换行符
 	data = normalizeLineEndings(data)
@@ -172,19 +242,22 @@ func (c *Converter) ParseTSV(data string) [][]string {
 	lines := strings.Split(data, "\n")
 
 	var rows [][]string
-	for _, line := range lines {
+	c.lineNumbers = nil
+	for i, line := range lines {
 		if strings.TrimSpace(line) == "" {
 			continue // 跳过空行
 		}
 		// 按制表符分割
 		cells := strings.Split(line, "\t")
 		rows = append(rows, cells)
+		c.lineNumbers = append(c.lineNumbers, offset+i+1)
 	}
 	return rows
 }
 
 // ParseColumn 解析 column 命令对齐格式的表格数据（使用多个空格对齐）
 func (c *Converter) ParseColumn(data string) [][]string {
+	offset := leadingLineCount(data)
 	data = strings.TrimSpace(data)
 	// 处理各种换行符
 	data = normalizeLineEndings(data)
@@ -195,7 +268,8 @@ func (c *Converter) ParseColumn(data string) [][]string {
 	// 首先分析所有行，找出列的分割位置
 	// 通过分析每行中多个连续空格的位置来确定列边界
 	var rows [][]string
-	for _, line := range lines {
+	c.lineNumbers = nil
+	for i, line := range lines {
 		line = strings.TrimRight(line, " \t") // 保留行尾空格用于对齐分析
 		if strings.TrimSpace(line) == "" {
 			continue // 跳过空行
@@ -225,27 +299,43 @@ func (c *Converter) ParseColumn(data string) [][]string {
 
 		if len(cleanedFields) > 0 {
 			rows = append(rows, cleanedFields)
+			c.lineNumbers = append(c.lineNumbers, offset+i+1)
 		}
 	}
 
 	return rows
 }
 
-// ParseTable 解析表格数据，自动检测格式（CSV、TSV 或 Column）
+// ParseTable 解析表格数据，自动检测格式（CSV、TSV、Column，HTML 为 true 时还有 HTML）
+// 解析后会清理单元格中的 BOM、不换行空格和零宽字符，见 SanitizeRows
 func (c *Converter) ParseTable(data string) ([][]string, error) {
+	// Excel 保存的 CSV 以 BOM 开头，会导致第一个带引号的字段解析失败
+	data = strings.TrimPrefix(data, "\uFEFF")
 	format := c.DetectFormat(data)
 
+	var rows [][]string
 	switch format {
+	case "html":
+		var err error
+		rows, err = ParseHTMLTable(data)
+		if err != nil {
+			return nil, err
+		}
+		// HTML 的行号没有意义，不报告
+		c.lineNumbers = nil
 	case "csv":
-		return c.ParseCSV(data)
+		var err error
+		rows, err = c.ParseCSV(data)
+		if err != nil {
+			return nil, err
+		}
 	case "column":
-		rows := c.ParseColumn(data)
-		return rows, nil
+		rows = c.ParseColumn(data)
 	default:
 		// TSV 格式（默认）
-		rows := c.ParseTSV(data)
-		return rows, nil
+		rows = c.ParseTSV(data)
 	}
+	return SanitizeRows(rows, c.NFC), nil
 }
 
 // ConvertToMarkdown 将表格数据转换为 Markdown 格式
@@ -254,8 +344,17 @@ func (c *Converter) ConvertToMarkdown(rows [][]string) string {
 		return ""
 	}
 
+	// 单元格数与表头不一致的行先补齐，多出的单元格扩展表头，保证每行的列数相同
+	rows, _, _ = NormalizeRows(rows, nil, "extend")
 	colAlignments, columnWidths := c.processHeader(rows)
 
+	switch c.Style {
+	case "compact":
+		return c.generateCompactTable(rows, colAlignments)
+	case "stable":
+		columnWidths = c.stableColumnWidths(rows[0], columnWidths)
+	}
+
 	// 生成 Markdown 行
 	var markdownRows []string
 
@@ -273,27 +372,51 @@ func (c *Converter) ConvertToMarkdown(rows [][]string) string {
 	return strings.Join(markdownRows, "\n")
 }
 
-// processHeader 处理表头，提取对齐信息和计算列宽
+// processHeader 处理表头，解析表头指令并计算列宽
+// 会原地修改 rows：移除指令、格式化数字、截断或换行超宽单元格、重命名并删除隐藏列
 func (c *Converter) processHeader(rows [][]string) ([]string, []int) {
+	directives := make([]HeaderDirective, len(rows[0]))
+	var hidden []int
+
+	// 处理表头，提取指令信息
+	for i, column := range rows[0] {
+		directive, name := ParseHeaderCell(column)
+		if c.Alignments != nil {
+			directive, name = HeaderDirective{}, column
+			if i < len(c.Alignments) {
+				directive.Alignment = c.Alignments[i]
+			}
+		}
+		directives[i] = directive
+		rows[0][i] = name
+
+		// 先格式化数字和重命名，再限制宽度、计算列宽，保证填充正确
+		c.applyNumberFormat(rows, i, directive.Format)
+		if directive.Name != "" {
+			rows[0][i] = directive.Name
+		}
+		if maxWidth := c.columnMaxWidth(name, i, directive.MaxWidth); maxWidth > 0 {
+			c.applyMaxWidth(rows, i, maxWidth)
+		}
+		if directive.Hidden {
+			hidden = append(hidden, i)
+		}
+	}
+
+	// 删除隐藏列
+	if len(hidden) > 0 {
+		for i := range rows {
+			rows[i] = removeColumns(rows[i], hidden)
+		}
+		directives = removeDirectives(directives, hidden)
+	}
+
 	colAlignments := make([]string, len(rows[0]))
 	columnWidths := make([]int, len(rows[0]))
-
-	// 处理表头，提取对齐信息
-	for i := range rows[0] {
+	for i, directive := range directives {
 		alignment := "l" // 默认左对齐
-		column := rows[0][i]
-
-		matches := alignmentRegex.FindStringSubmatch(column)
-		if len(matches) > 0 {
-			align := strings.ToLower(string(matches[1][1]))
-			switch align {
-			case "c":
-				alignment = "c"
-			case "r":
-				alignment = "r"
-			}
-			// 移除对齐标记
-			rows[0][i] = alignmentRegex.ReplaceAllString(column, "")
+		if directive.Alignment != "" {
+			alignment = directive.Alignment
 		}
 		colAlignments[i] = alignment
 		columnWidths[i] = c.ColumnWidth(rows, i)
@@ -302,11 +425,63 @@ func (c *Converter) processHeader(rows [][]string) ([]string, []int) {
 	return colAlignments, columnWidths
 }
 
+// applyNumberFormat 对指定列的数据行应用数字格式
+// 命令行指定的格式优先于表头指令中的格式，聚合列也会匹配按源列名指定的格式
+func (c *Converter) applyNumberFormat(rows [][]string, columnIndex int, headerFormat *NumberFormat) {
+	format, ok := c.NumberFormats[rows[0][columnIndex]]
+	if !ok {
+		format, ok = c.NumberFormats[strconv.Itoa(columnIndex+1)]
+	}
+	if source, isAggregate := aggregateSource(rows[0][columnIndex]); !ok && isAggregate {
+		// 分组后的聚合列（sum(revenue)）沿用源列的格式
+		format, ok = c.NumberFormats[source]
+	}
+	if !ok && headerFormat != nil {
+		format, ok = *headerFormat, true
+	}
+	if !ok {
+		return
+	}
+	for _, row := range rows[1:] {
+		if columnIndex < len(row) {
+			row[columnIndex] = FormatNumber(row[columnIndex], format)
+		}
+	}
+}
+
+// removeColumns 删除行中指定序号的列（序号按升序排列）
+func removeColumns(row []string, indexes []int) []string {
+	result := row[:0]
+	next := 0
+	for i, cell := range row {
+		if next < len(indexes) && indexes[next] == i {
+			next++
+			continue
+		}
+		result = append(result, cell)
+	}
+	return result
+}
+
+// removeDirectives 删除指定序号的表头指令（序号按升序排列）
+func removeDirectives(directives []HeaderDirective, indexes []int) []HeaderDirective {
+	result := directives[:0]
+	next := 0
+	for i, directive := range directives {
+		if next < len(indexes) && indexes[next] == i {
+			next++
+			continue
+		}
+		result = append(result, directive)
+	}
+	return result
+}
+
 // generateHeaderRow 生成表头行
 func (c *Converter) generateHeaderRow(header []string, columnWidths []int) string {
 	var cells []string
 	for i, cell := range header {
-		cellDisplayWidth := c.DisplayWidth(cell)
+		cellDisplayWidth := c.cellWidth(cell)
 		padding := strings.Repeat(" ", columnWidths[i]-cellDisplayWidth+1)
 		cells = append(cells, cell+padding)
 	}
@@ -341,34 +516,75 @@ func (c *Converter) generateSeparatorRow(columnWidths []int, colAlignments []str
 func (c *Converter) generateDataRow(row []string, columnWidths []int) string {
 	var cells []string
 	for j, cell := range row {
-		if j < len(columnWidths) {
-			cellDisplayWidth := c.DisplayWidth(cell)
-			padding := strings.Repeat(" ", columnWidths[j]-cellDisplayWidth+1)
-			cells = append(cells, cell+padding)
-		} else {
-			cells = append(cells, cell)
-		}
+		cellDisplayWidth := c.cellWidth(cell)
+		padding := strings.Repeat(" ", columnWidths[j]-cellDisplayWidth+1)
+		cells = append(cells, cell+padding)
 	}
 	return "| " + strings.Join(cells, " | ") + " |"
 }
 
-// filterEmptyRows 过滤空行
-func (c *Converter) filterEmptyRows(rows [][]string) [][]string {
-	var filteredRows [][]string
-	for _, row := range rows {
-		// 检查是否所有字段都为空
-		allEmpty := true
-		for _, cell := range row {
-			if strings.TrimSpace(cell) != "" {
-				allEmpty = false
-				break
-			}
+// stableColumnWidths 沿用上一版本表格中同名列的宽度，内容变宽时才扩展
+// 这样单元格变化时其他行不会重新填充，文档的 diff 更小
+func (c *Converter) stableColumnWidths(header []string, columnWidths []int) []int {
+	if len(c.Previous) == 0 {
+		return columnWidths
+	}
+
+	previousWidths := make(map[string]int)
+	for i, name := range c.Previous[0] {
+		previousWidths[name] = c.ColumnWidth(c.Previous, i)
+	}
+
+	widths := make([]int, len(columnWidths))
+	for i, width := range columnWidths {
+		widths[i] = max(width, previousWidths[header[i]])
+	}
+	return widths
+}
+
+// generateCompactTable 生成不填充空格的紧凑表格，例如 |a|b|
+func (c *Converter) generateCompactTable(rows [][]string, colAlignments []string) string {
+	markdownRows := make([]string, 0, len(rows)+1)
+	markdownRows = append(markdownRows, "|"+strings.Join(rows[0], "|")+"|")
+
+	separators := make([]string, len(colAlignments))
+	for i, alignment := range colAlignments {
+		switch alignment {
+		case "r":
+			separators[i] = "--:"
+		case "c":
+			separators[i] = ":-:"
+		default:
+			separators[i] = "---"
 		}
-		if !allEmpty {
-			filteredRows = append(filteredRows, row)
+	}
+	markdownRows = append(markdownRows, "|"+strings.Join(separators, "|")+"|")
+
+	for _, row := range rows[1:] {
+		markdownRows = append(markdownRows, "|"+strings.Join(row, "|")+"|")
+	}
+	return strings.Join(markdownRows, "\n")
+}
+
+// LineNumbers 返回最近一次解析得到的每行数据在输入中的行号（从 1 开始）
+func (c *Converter) LineNumbers() []int {
+	return c.lineNumbers
+}
+
+// isEmptyRow 检查是否所有字段都为空
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
 		}
 	}
-	return filteredRows
+	return true
+}
+
+// leadingLineCount 返回数据开头空白部分包含的换行数，用于修正去掉首部空白后的行号
+func leadingLineCount(data string) int {
+	leading := data[:len(data)-len(strings.TrimLeftFunc(data, unicode.IsSpace))]
+	return strings.Count(normalizeLineEndings(leading), "\n")
 }
 
 // normalizeLineEndings 标准化换行符
@@ -20,6 +20,43 @@ func TestDisplayWidth(t *testing.T) {
 		{"空字符串", "", 0},
 		{"日文", "こんにちは", 10},
 		{"韩文", "안녕하세요", 10},
+		{"中文标点", "，。「」", 8},
+		{"全角空格", "\u3000", 2},
+		{"emoji", "😀", 2},
+		{"emoji 变体选择符", "❤️", 2},
+		{"emoji 文本样式", "☺", 1},
+		{"ZWJ 组合 emoji", "👨\u200d👩\u200d👧", 2},
+		{"肤色修饰 emoji", "👍🏽", 2},
+		{"国旗", "🇨🇳", 2},
+		{"组合重音", "e\u0301", 1},
+		{"零宽空格", "a\u200bb", 2},
+		{"半角片假名", "ｱｲｳ", 3},
+		{"歧义宽度默认为窄", "①Ω", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := converter.DisplayWidth(tt.input)
+			if result != tt.expected {
+				t.Errorf("DisplayWidth(%q) = %d, 期望 %d", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDisplayWidthAmbiguousWide(t *testing.T) {
+	converter := NewConverter()
+	converter.AmbiguousWide = true
+
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{"圈数字", "①②", 4},
+		{"希腊字母", "Ω", 2},
+		{"英文不受影响", "abc", 3},
+		{"中文不受影响", "你好", 4},
 	}
 
 	for _, tt := range tests {
@@ -81,6 +118,7 @@ func TestDetectFormat(t *testing.T) {
 		{"单个空格不是Column格式", "Name Age City", "tsv"},
 		{"有逗号时不识别为Column", "Name    Age,City", "csv"},
 		{"有制表符时不识别为Column", "Name    Age\tCity", "tsv"},
+		{"未启用HTML时不识别HTML", "<table><tr><td>a,b</td></tr></table>", "csv"},
 	}
 
 	for _, tt := range tests {
@@ -422,6 +460,15 @@ func TestConvertToMarkdown(t *testing.T) {
 			[][]string{},
 			"",
 		},
+		{
+			"不规则的行",
+			[][]string{
+				{"a", "b"},
+				{"1"},
+				{"2", "3", "4"},
+			},
+			"| a  | b  | Column 3  |\n|----|----|-----------|\n| 1  |    |           |\n| 2  | 3  | 4         |",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// diffContext 统一差异格式中每个变更块前后保留的上下文行数
+const diffContext = 3
+
+// diffOp 差异中的一行：' ' 未变、'-' 删除、'+' 新增
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// DiffRegion 旧文档中被替换的一段连续行
+type DiffRegion struct {
+	Start int      // 区域在旧文档中的第一行（从 0 开始）
+	Old   []string // 区域内原有的行
+	New   []string // 替换后的行
+}
+
+// UnifiedDiff 生成两段文本的统一差异格式（unified diff），没有差异的行时返回空字符串
+// 逐行比较时忽略换行符的差异
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	return UnifiedRegionDiff(oldName, newName, nil, []DiffRegion{{Old: splitLines(oldText), New: splitLines(newText)}})
+}
+
+// UnifiedRegionDiff 生成旧文档 lines 中若干区域被替换后的统一差异，区域按位置排列且互不重叠
+// 每个区域单独比较，区域外的行视为相同：最长公共子序列表的大小只与最大的区域有关，
+// 而逐行差异（ops）仍包含文档的每一行，占用与文档行数成正比的内存
+func UnifiedRegionDiff(oldName, newName string, lines []string, regions []DiffRegion) string {
+	var ops []diffOp
+	next := 0
+	for _, region := range regions {
+		for _, line := range lines[next:region.Start] {
+			ops = append(ops, diffOp{' ', line})
+		}
+		ops = append(ops, diffLines(region.Old, region.New)...)
+		next = region.Start + len(region.Old)
+	}
+	for _, line := range lines[min(next, len(lines)):] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	if !slices.ContainsFunc(ops, func(op diffOp) bool { return op.kind != ' ' }) {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	// 找出所有变更行，把相距不超过 2*diffContext 的变更合并为一个块
+	oldLine, newLine := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if op.kind != '+' {
+			oldLine[i+1]++
+		}
+		if op.kind != '-' {
+			newLine[i+1]++
+		}
+	}
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := max(i-diffContext, 0)
+		end := i
+		for j := i; j < len(ops) && j <= end+2*diffContext; j++ {
+			if ops[j].kind != ' ' {
+				end = j
+			}
+		}
+		end = min(end+diffContext+1, len(ops))
+
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(oldLine[start], oldLine[end]-oldLine[start]),
+			hunkRange(newLine[start], newLine[end]-newLine[start]))
+		for _, op := range ops[start:end] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			b.WriteByte('\n')
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange 格式化变更块的行范围，before 为块之前的行数
+func hunkRange(before, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// splitLines 将文本拆分为行，忽略末尾的换行符
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(normalizeLineEndings(text), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diffLines 基于最长公共子序列计算逐行差异，需要 O(n·m) 内存
+// 先去掉相同的开头和结尾；比较整个文档时应改用 UnifiedRegionDiff，只比较发生变化的区域
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	// lcs[i][j] 为 midA[i:] 与 midB[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			ops = append(ops, diffOp{' ', midA[i]})
+			i++
+			j++
+		case j == len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', midA[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', midB[j]})
+			j++
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
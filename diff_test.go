@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	numbered := func(n int) []string {
+		lines := make([]string, n)
+		for i := range lines {
+			lines[i] = string(rune('a' + i))
+		}
+		return lines
+	}
+	long := numbered(20)
+	changed := append([]string{}, long...)
+	changed[1] = "B"
+	changed[17] = "R"
+
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{"内容相同", "a\nb\n", "a\nb\n", ""},
+		{"只有换行符不同", "a\r\nb\n", "a\nb\r\n", ""},
+		{
+			"修改一行",
+			"a\nb\nc\n",
+			"a\nB\nc\n",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"新增行",
+			"a\n",
+			"a\nb\nc\n",
+			"--- old\n+++ new\n@@ -1 +1,3 @@\n a\n+b\n+c\n",
+		},
+		{
+			"从空文件新增",
+			"",
+			"a\n",
+			"--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			"相距较远的修改分为两个块",
+			strings.Join(long, "\n"),
+			strings.Join(changed, "\n"),
+			"--- old\n+++ new\n" +
+				"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+				"@@ -15,6 +15,6 @@\n o\n p\n q\n-r\n+R\n s\n t\n",
+		},
+		{
+			"CRLF 换行",
+			"a\r\nb\r\n",
+			"a\r\nc\r\n",
+			"--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := UnifiedDiff("old", "new", tt.old, tt.new)
+			if result != tt.expected {
+				t.Errorf("UnifiedDiff() =\n%s\n期望\n%s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnifiedRegionDiff(t *testing.T) {
+	lines := []string{"# Title", "<!-- a -->", "| a |", "<!-- /a -->", "text", "text", "text", "text", "<!-- b -->", "| b |", "<!-- /b -->"}
+	regions := []DiffRegion{
+		{Start: 2, Old: []string{"| a |"}, New: []string{"| a |", "| 1 |"}},
+		{Start: 9, Old: []string{"| b |"}, New: []string{"| B |"}},
+	}
+	expected := "--- old\n+++ new\n" +
+		"@@ -1,6 +1,7 @@\n # Title\n <!-- a -->\n | a |\n+| 1 |\n <!-- /a -->\n text\n text\n" +
+		"@@ -7,5 +8,5 @@\n text\n text\n <!-- b -->\n-| b |\n+| B |\n <!-- /b -->\n"
+	if result := UnifiedRegionDiff("old", "new", lines, regions); result != expected {
+		t.Errorf("UnifiedRegionDiff() =\n%s\n期望\n%s", result, expected)
+	}
+
+	unchanged := []DiffRegion{{Start: 2, Old: []string{"| a |"}, New: []string{"| a |"}}}
+	if result := UnifiedRegionDiff("old", "new", lines, unchanged); result != "" {
+		t.Errorf("UnifiedRegionDiff() = %q, 期望没有差异", result)
+	}
+}
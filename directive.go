@@ -0,0 +1,125 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// widthDirectiveRegex matches max width directives: w30
+	widthDirectiveRegex = regexp.MustCompile(`^(?i)w(\d+)$`)
+	// renameDirectiveRegex matches rename directives: as(New Name)
+	renameDirectiveRegex = regexp.MustCompile(`^(?i)as\((.*)\)$`)
+)
+
+// HeaderDirective 表头指令，控制列的对齐、数字格式、最大宽度、隐藏和重命名
+// 语法: ^r:num(2):w30:hide:as(新列名) 列名
+// 兼容旧语法: ^l、^c、^r 直接跟列名，例如 ^rPrice
+// 空指令 "^ " 表示后面的列名不含指令，用于以 ^ 开头的列名，例如 "^ ^rPrice"
+type HeaderDirective struct {
+	Alignment string        // 对齐方式 "l"、"c"、"r"，空表示未指定
+	Format    *NumberFormat // 数字格式，nil 表示未指定
+	MaxWidth  int           // 最大显示宽度，0 表示不限制
+	Hidden    bool          // 是否隐藏该列
+	Name      string        // 重命名后的列名，空表示不重命名
+}
+
+// ParseHeaderCell 解析表头单元格中的指令，返回指令和去掉指令后的列名
+// 指令无法识别时整个单元格按普通列名处理
+func ParseHeaderCell(cell string) (HeaderDirective, string) {
+	if !strings.HasPrefix(cell, "^") || len(cell) < 2 {
+		return HeaderDirective{}, cell
+	}
+
+	// 旧语法：^l/^c/^r 后面直接跟列名
+	first := strings.ToLower(cell[1:2])
+	if strings.Contains("lcr", first) && (len(cell) == 2 || cell[2] != ':') {
+		return HeaderDirective{Alignment: first}, strings.TrimLeftFunc(cell[2:], unicode.IsSpace)
+	}
+
+	tokens, rest := splitDirectiveTokens(cell[1:])
+	if len(tokens) == 1 && tokens[0] == "" && rest != "" {
+		return HeaderDirective{}, strings.TrimLeftFunc(rest, unicode.IsSpace)
+	}
+	var directive HeaderDirective
+	for _, token := range tokens {
+		if !directive.apply(token) {
+			return HeaderDirective{}, cell
+		}
+	}
+	return directive, strings.TrimLeftFunc(rest, unicode.IsSpace)
+}
+
+// HeaderName 返回表头单元格去掉指令后的列名
+func HeaderName(cell string) string {
+	_, name := ParseHeaderCell(cell)
+	return name
+}
+
+// directiveHeader 用指令和列名组成表头单元格，只保留对齐、数字格式和宽度指令
+// 没有指令且列名以 ^ 开头时使用空指令，避免列名被解析为指令
+func directiveHeader(directive HeaderDirective, name string) string {
+	var tokens []string
+	if directive.Alignment != "" {
+		tokens = append(tokens, directive.Alignment)
+	}
+	if directive.Format != nil {
+		tokens = append(tokens, directive.Format.String())
+	}
+	if directive.MaxWidth > 0 {
+		tokens = append(tokens, "w"+strconv.Itoa(directive.MaxWidth))
+	}
+	if len(tokens) == 0 && !strings.HasPrefix(name, "^") {
+		return name
+	}
+	return "^" + strings.Join(tokens, ":") + " " + name
+}
+
+// splitDirectiveTokens 按冒号拆分指令，遇到括号外的空白字符时结束
+func splitDirectiveTokens(s string) ([]string, string) {
+	var tokens []string
+	depth := 0
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case r == ':' && depth == 0:
+			tokens = append(tokens, s[start:i])
+			start = i + 1
+		case unicode.IsSpace(r) && depth == 0:
+			return append(tokens, s[start:i]), s[i:]
+		}
+	}
+	return append(tokens, s[start:]), ""
+}
+
+// apply 将单个指令应用到 HeaderDirective，无法识别时返回 false
+func (d *HeaderDirective) apply(token string) bool {
+	lower := strings.ToLower(token)
+	switch {
+	case lower == "l" || lower == "c" || lower == "r":
+		d.Alignment = lower
+	case lower == "hide":
+		d.Hidden = true
+	case widthDirectiveRegex.MatchString(token):
+		width, err := strconv.Atoi(widthDirectiveRegex.FindStringSubmatch(token)[1])
+		if err != nil || width == 0 {
+			return false
+		}
+		d.MaxWidth = width
+	case renameDirectiveRegex.MatchString(token):
+		d.Name = strings.TrimSpace(renameDirectiveRegex.FindStringSubmatch(token)[1])
+	default:
+		format, err := ParseNumberFormat(token)
+		if err != nil {
+			return false
+		}
+		d.Format = &format
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHeaderCell(t *testing.T) {
+	num2 := NumberFormat{"num", 2}
+
+	tests := []struct {
+		name              string
+		input             string
+		expectedDirective HeaderDirective
+		expectedName      string
+	}{
+		{"普通列名", "Price", HeaderDirective{}, "Price"},
+		{"旧语法右对齐", "^rPrice", HeaderDirective{Alignment: "r"}, "Price"},
+		{"旧语法大写", "^CColor", HeaderDirective{Alignment: "c"}, "Color"},
+		{"只有对齐标记", "^l", HeaderDirective{Alignment: "l"}, ""},
+		{"对齐和格式", "^r:num(2) Price", HeaderDirective{Alignment: "r", Format: &num2}, "Price"},
+		{"完整指令", "^c:w30:hide Notes", HeaderDirective{Alignment: "c", MaxWidth: 30, Hidden: true}, "Notes"},
+		{"重命名", "^as(Unit Price) price_usd", HeaderDirective{Name: "Unit Price"}, "price_usd"},
+		{"只有格式", "^num(2):as(金额) amount", HeaderDirective{Format: &num2, Name: "金额"}, "amount"},
+		{"无法识别的指令", "^Total", HeaderDirective{}, "^Total"},
+		{"部分无法识别", "^r:bold Price", HeaderDirective{}, "^r:bold Price"},
+		{"零宽度", "^w0 Price", HeaderDirective{}, "^w0 Price"},
+		{"只有插入符", "^", HeaderDirective{}, "^"},
+		{"旧语法后的空格", "^r Price", HeaderDirective{Alignment: "r"}, "Price"},
+		{"空指令", "^ ^rPrice", HeaderDirective{}, "^rPrice"},
+		{"空指令后的普通列名", "^ Price", HeaderDirective{}, "Price"},
+		{"空指令后多个空格", "^   ^w30 Notes", HeaderDirective{}, "^w30 Notes"},
+		{"空指令后只有插入符", "^ ^", HeaderDirective{}, "^"},
+		{"冒号开头不是空指令", "^:r Price", HeaderDirective{}, "^:r Price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directive, name := ParseHeaderCell(tt.input)
+			if !reflect.DeepEqual(directive, tt.expectedDirective) {
+				t.Errorf("ParseHeaderCell(%q) 指令 = %+v, 期望 %+v", tt.input, directive, tt.expectedDirective)
+			}
+			if name != tt.expectedName {
+				t.Errorf("ParseHeaderCell(%q) 列名 = %q, 期望 %q", tt.input, name, tt.expectedName)
+			}
+		})
+	}
+}
+
+func TestDirectiveHeader(t *testing.T) {
+	num2 := NumberFormat{"num", 2}
+
+	tests := []struct {
+		name      string
+		directive HeaderDirective
+		column    string
+		expected  string
+	}{
+		{"没有指令", HeaderDirective{}, "Price", "Price"},
+		{"对齐", HeaderDirective{Alignment: "r"}, "Price", "^r Price"},
+		{"对齐、格式和宽度", HeaderDirective{Alignment: "c", Format: &num2, MaxWidth: 30}, "sum(Price)", "^c:num(2):w30 sum(Price)"},
+		{"不保留隐藏和重命名", HeaderDirective{Hidden: true, Name: "x"}, "Price", "Price"},
+		{"以插入符开头的列名", HeaderDirective{}, "^rhigh", "^ ^rhigh"},
+		{"指令和以插入符开头的列名", HeaderDirective{Alignment: "r"}, "^w30", "^r ^w30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cell := directiveHeader(tt.directive, tt.column)
+			if cell != tt.expected {
+				t.Errorf("directiveHeader() = %q, 期望 %q", cell, tt.expected)
+			}
+			// 生成的表头单元格应能解析回相同的指令和列名
+			directive, name := ParseHeaderCell(cell)
+			if name != tt.column {
+				t.Errorf("ParseHeaderCell(%q) 列名 = %q, 期望 %q", cell, name, tt.column)
+			}
+			expected := HeaderDirective{Alignment: tt.directive.Alignment, Format: tt.directive.Format, MaxWidth: tt.directive.MaxWidth}
+			if !reflect.DeepEqual(directive, expected) {
+				t.Errorf("ParseHeaderCell(%q) 指令 = %+v, 期望 %+v", cell, directive, expected)
+			}
+		})
+	}
+}
+
+func TestConvertToMarkdownWithDirectives(t *testing.T) {
+	converter := NewConverter()
+
+	tests := []struct {
+		name     string
+		input    [][]string
+		expected string
+	}{
+		{
+			"隐藏列",
+			[][]string{
+				{"Name", "^hide Secret", "^r:fixed(1) Score"},
+				{"Jane", "xyz", "9.26"},
+			},
+			"| Name  | Score  |\n|-------|-------:|\n| Jane  | 9.3    |",
+		},
+		{
+			"重命名",
+			[][]string{
+				{"^c:as(Full Name) name"},
+				{"Jane"},
+			},
+			"| Full Name  |\n|:----------:|\n| Jane       |",
+		},
+		{
+			"最大宽度",
+			[][]string{
+				{"^w6 Notes"},
+				{"Hello world"},
+				{"你好世界"},
+			},
+			"| Notes   |\n|---------|\n| Hello…  |\n| 你好…   |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := converter.ConvertToMarkdown(tt.input)
+			if result != tt.expected {
+				t.Errorf("ConvertToMarkdown() = %q, 期望 %q", result, tt.expected)
+			}
+		})
+	}
+}
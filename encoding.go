@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	unicodeenc "golang.org/x/text/encoding/unicode"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DecodeInput 将输入字节转换为 UTF-8 字符串
+// name 为编码名称（例如 utf-16le、gbk、shift_jis、windows-1252），为空或 "auto" 时自动检测
+func DecodeInput(data []byte, name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name != "" && name != "auto" {
+		enc, err := htmlindex.Get(name)
+		if err != nil {
+			return "", fmt.Errorf("unsupported encoding %q", name)
+		}
+		return decodeWith(enc, data)
+	}
+
+	enc, _ := DetectEncoding(data)
+	return decodeWith(enc, data)
+}
+
+// DetectEncoding 检测输入的编码，返回编码和名称
+// 检测顺序：BOM → 无 BOM 的 UTF-16 → 合法的 UTF-8 → Shift-JIS（包含假名）→ GBK → Windows-1252
+// 这是启发式检测，无法确定时可以用 -encoding 指定
+func DetectEncoding(data []byte) (encoding.Encoding, string) {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return unicodeenc.UTF8BOM, "utf-8"
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return unicodeenc.UTF16(unicodeenc.LittleEndian, unicodeenc.ExpectBOM), "utf-16le"
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return unicodeenc.UTF16(unicodeenc.BigEndian, unicodeenc.ExpectBOM), "utf-16be"
+	}
+
+	// UTF-16 编码的 ASCII 文本也是合法的 UTF-8（包含 NUL），需要先检查
+	if enc, name, ok := guessUTF16(data); ok {
+		return enc, name
+	}
+	if utf8.Valid(data) {
+		return unicodeenc.UTF8, "utf-8"
+	}
+
+	// 日文文本几乎总会包含全角假名，而 GBK 文本按 Shift-JIS 解码时会出现大量半角片假名
+	if text, ok := decodeStrict(japanese.ShiftJIS, data); ok && containsKana(text) && !containsHalfwidthKana(text) {
+		return japanese.ShiftJIS, "shift_jis"
+	}
+	if _, ok := decodeStrict(simplifiedchinese.GB18030, data); ok && highBytesPaired(data) {
+		return simplifiedchinese.GBK, "gbk"
+	}
+	return charmap.Windows1252, "windows-1252"
+}
+
+// decodeWith 使用指定编码解码
+func decodeWith(enc encoding.Encoding, data []byte) (string, error) {
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", err
+	}
+	// UTF-8 BOM 可能在指定编码时仍然存在
+	return string(bytes.TrimPrefix(decoded, bomUTF8)), nil
+}
+
+// decodeStrict 解码并检查是否出现无法识别的字节（替换字符）
+func decodeStrict(enc encoding.Encoding, data []byte) (string, bool) {
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil || bytes.ContainsRune(decoded, utf8.RuneError) {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// guessUTF16 根据零字节的位置猜测没有 BOM 的 UTF-16 的字节序
+// 表格数据大多是 ASCII，UTF-16 编码后每两个字节中有一个是 0
+func guessUTF16(data []byte) (encoding.Encoding, string, bool) {
+	if len(data) < 2 || len(data)%2 != 0 {
+		return nil, "", false
+	}
+	evenZeros, oddZeros := 0, 0
+	for i := 0; i+1 < len(data); i += 2 {
+		if data[i] == 0 {
+			evenZeros++
+		}
+		if data[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	pairs := len(data) / 2
+	switch {
+	case oddZeros*10 >= pairs*3 && evenZeros*10 < pairs:
+		return unicodeenc.UTF16(unicodeenc.LittleEndian, unicodeenc.IgnoreBOM), "utf-16le", true
+	case evenZeros*10 >= pairs*3 && oddZeros*10 < pairs:
+		return unicodeenc.UTF16(unicodeenc.BigEndian, unicodeenc.IgnoreBOM), "utf-16be", true
+	}
+	return nil, "", false
+}
+
+// highBytesPaired 检查非 ASCII 字节是否成对出现（双字节编码的特征）
+// Windows-1252 等单字节编码中，非 ASCII 字节通常单独出现在 ASCII 字母之间
+func highBytesPaired(data []byte) bool {
+	run := 0
+	for _, b := range data {
+		if b >= 0x80 {
+			run++
+			continue
+		}
+		if run%2 != 0 {
+			return false
+		}
+		run = 0
+	}
+	return run%2 == 0
+}
+
+// containsKana 检查文本是否包含平假名或全角片假名
+func containsKana(text string) bool {
+	for _, r := range text {
+		if unicode.Is(unicode.Hiragana, r) || (unicode.Is(unicode.Katakana, r) && r < 0xFF00) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsHalfwidthKana 检查文本是否包含半角片假名
+func containsHalfwidthKana(text string) bool {
+	for _, r := range text {
+		if r >= 0xFF61 && r <= 0xFF9F {
+			return true
+		}
+	}
+	return false
+}
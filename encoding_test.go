@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	unicodeenc "golang.org/x/text/encoding/unicode"
+)
+
+// mustEncode 将 UTF-8 文本编码为指定编码的字节
+func mustEncode(t *testing.T, enc encoding.Encoding, text string) []byte {
+	t.Helper()
+	data, err := enc.NewEncoder().Bytes([]byte(text))
+	if err != nil {
+		t.Fatalf("编码 %q 失败: %v", text, err)
+	}
+	return data
+}
+
+func TestDetectEncoding(t *testing.T) {
+	utf16LEBOM := unicodeenc.UTF16(unicodeenc.LittleEndian, unicodeenc.UseBOM)
+	utf16BEBOM := unicodeenc.UTF16(unicodeenc.BigEndian, unicodeenc.UseBOM)
+	utf16LE := unicodeenc.UTF16(unicodeenc.LittleEndian, unicodeenc.IgnoreBOM)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"UTF-8", []byte("Name\t名前\nJane\t1"), "utf-8"},
+		{"UTF-8 BOM", append([]byte{0xEF, 0xBB, 0xBF}, "Name,Age"...), "utf-8"},
+		{"UTF-16LE BOM", mustEncode(t, utf16LEBOM, "Name\tAge\r\nJane\t30"), "utf-16le"},
+		{"UTF-16BE BOM", mustEncode(t, utf16BEBOM, "Name\tAge"), "utf-16be"},
+		{"UTF-16LE 无 BOM", mustEncode(t, utf16LE, "Name\tAge\r\nJane\t30"), "utf-16le"},
+		{"GBK", mustEncode(t, simplifiedchinese.GBK, "姓名,年龄\n张三,30"), "gbk"},
+		{"Shift-JIS", mustEncode(t, japanese.ShiftJIS, "名前,年齢\nテスト,30\nやまだ,25"), "shift_jis"},
+		{"Windows-1252", mustEncode(t, charmap.Windows1252, "Größe,Preis\nMüller,1 €"), "windows-1252"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, name := DetectEncoding(tt.data)
+			if name != tt.expected {
+				t.Errorf("DetectEncoding() = %s, 期望 %s", name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		encoding string
+		expected string
+		wantErr  bool
+	}{
+		{"自动检测 UTF-8 BOM", []byte("\xEF\xBB\xBFName,Age"), "auto", "Name,Age", false},
+		{"自动检测 GBK", mustEncode(t, simplifiedchinese.GBK, "姓名,年龄"), "", "姓名,年龄", false},
+		{
+			"自动检测 UTF-16LE BOM",
+			mustEncode(t, unicodeenc.UTF16(unicodeenc.LittleEndian, unicodeenc.UseBOM), "名前\t値"),
+			"auto",
+			"名前\t値",
+			false,
+		},
+		{"指定编码", mustEncode(t, charmap.Windows1252, "café"), "windows-1252", "café", false},
+		{"指定编码别名", mustEncode(t, japanese.ShiftJIS, "テスト"), "Shift_JIS", "テスト", false},
+		{"指定编码时去掉 UTF-8 BOM", []byte("\xEF\xBB\xBFa,b"), "utf-8", "a,b", false},
+		{"不支持的编码", []byte("a,b"), "klingon", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DecodeInput(tt.data, tt.encoding)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeInput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && result != tt.expected {
+				t.Errorf("DecodeInput() = %q, 期望 %q", result, tt.expected)
+			}
+		})
+	}
+}
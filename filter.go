@@ -0,0 +1,381 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// FilterRows 使用过滤表达式筛选数据行，表头行始终保留
+// 表达式示例: status == "open" && priority <= 2、name =~ "^J"、!(`Due Date` == "")
+func FilterRows(rows [][]string, expr string) ([][]string, error) {
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	filter, err := ParseFilter(expr, rows[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := [][]string{rows[0]}
+	for _, row := range rows[1:] {
+		if filter.Match(row) {
+			result = append(result, row)
+		}
+	}
+	return result, nil
+}
+
+// Filter 已解析的行过滤表达式
+type Filter struct {
+	root boolNode
+}
+
+// ParseFilter 解析过滤表达式，表达式中的列名按表头解析（忽略表头指令）
+func ParseFilter(expr string, header []string) (*Filter, error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens, header: header}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+	return &Filter{root: root}, nil
+}
+
+// Match 判断数据行是否满足过滤条件
+func (f *Filter) Match(row []string) bool {
+	return f.root.match(row)
+}
+
+// boolNode 返回布尔值的表达式节点
+type boolNode interface {
+	match(row []string) bool
+}
+
+// valueNode 返回单元格值的表达式节点
+type valueNode interface {
+	value(row []string) string
+}
+
+type orNode struct{ left, right boolNode }
+
+func (n orNode) match(row []string) bool { return n.left.match(row) || n.right.match(row) }
+
+type andNode struct{ left, right boolNode }
+
+func (n andNode) match(row []string) bool { return n.left.match(row) && n.right.match(row) }
+
+type notNode struct{ operand boolNode }
+
+func (n notNode) match(row []string) bool { return !n.operand.match(row) }
+
+// truthyNode 单独出现的操作数，非空即为真
+type truthyNode struct{ operand valueNode }
+
+func (n truthyNode) match(row []string) bool {
+	return strings.TrimSpace(n.operand.value(row)) != ""
+}
+
+// compareNode 比较两个值，两边都是数字时按数值比较，否则按字符串比较
+// 只有一边是数字时（例如空单元格或 N/A 与数字比较），<、<=、>、>= 不成立
+type compareNode struct {
+	op          string
+	left, right valueNode
+}
+
+func (n compareNode) match(row []string) bool {
+	left, right := n.left.value(row), n.right.value(row)
+	cmp := 0
+	leftNumber, leftOK := parseNumber(left)
+	rightNumber, rightOK := parseNumber(right)
+	if leftOK && rightOK {
+		switch {
+		case leftNumber < rightNumber:
+			cmp = -1
+		case leftNumber > rightNumber:
+			cmp = 1
+		}
+	} else if (leftOK || rightOK) && n.op != "==" && n.op != "!=" {
+		return false
+	} else {
+		cmp = strings.Compare(strings.TrimSpace(left), strings.TrimSpace(right))
+	}
+
+	switch n.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default: // ">="
+		return cmp >= 0
+	}
+}
+
+// regexNode 正则匹配，=~ 匹配、!~ 不匹配
+type regexNode struct {
+	negate  bool
+	operand valueNode
+	re      *regexp.Regexp
+}
+
+func (n regexNode) match(row []string) bool {
+	return n.re.MatchString(n.operand.value(row)) != n.negate
+}
+
+type columnNode struct{ index int }
+
+func (n columnNode) value(row []string) string {
+	if n.index < len(row) {
+		return row[n.index]
+	}
+	return ""
+}
+
+type literalNode struct{ text string }
+
+func (n literalNode) value([]string) string { return n.text }
+
+type filterTokenKind int
+
+const (
+	tokenEOF filterTokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOp
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+// filterOperators 按长度从长到短排列，保证优先匹配双字符运算符
+var filterOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!", "(", ")"}
+
+// tokenizeFilter 将过滤表达式拆分为词法单元
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'' || r == '`':
+			// 字符串字面量；反引号用于包含空格等特殊字符的列名
+			text, next, err := scanQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			kind := tokenString
+			if r == '`' {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, filterToken{kind, text, i})
+			i = next
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, filterToken{tokenNumber, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, filterToken{tokenIdent, string(runes[start:i]), start})
+		default:
+			op := ""
+			for _, candidate := range filterOperators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i+1)
+			}
+			tokens = append(tokens, filterToken{tokenOp, op, i})
+			i += len([]rune(op))
+		}
+	}
+	return append(tokens, filterToken{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// scanQuoted 读取引号包围的内容，支持反斜杠转义
+func scanQuoted(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				b.WriteRune(runes[i])
+			}
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated %c at position %d", quote, start+1)
+}
+
+// filterParser 递归下降解析器
+// 优先级从低到高: ||, &&, !, 比较运算
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+	header []string
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *filterParser) acceptOp(op string) bool {
+	if tok := p.peek(); tok.kind == tokenOp && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (boolNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (boolNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("&&") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (boolNode, error) {
+	if p.acceptOp("!") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (boolNode, error) {
+	if p.acceptOp("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptOp(")") {
+			tok := p.peek()
+			return nil, fmt.Errorf("expected \")\" at position %d", tok.pos+1)
+		}
+		return node, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	if tok.kind != tokenOp {
+		return truthyNode{left}, nil
+	}
+	switch tok.text {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{tok.text, left, right}, nil
+	case "=~", "!~":
+		p.next()
+		pattern := p.next()
+		if pattern.kind != tokenString {
+			return nil, fmt.Errorf("expected string pattern after %s at position %d", tok.text, pattern.pos+1)
+		}
+		re, err := regexp.Compile(pattern.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pattern.text, err)
+		}
+		return regexNode{tok.text == "!~", left, re}, nil
+	}
+	return truthyNode{left}, nil
+}
+
+func (p *filterParser) parseOperand() (valueNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString, tokenNumber:
+		return literalNode{tok.text}, nil
+	case tokenIdent:
+		index, err := findColumnByName(p.header, tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", tok.text, strings.Join(headerNames(p.header), ", "))
+		}
+		return columnNode{index}, nil
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+}
+
+// headerNames 返回去掉表头指令后的列名列表
+func headerNames(header []string) []string {
+	names := make([]string, len(header))
+	for i, cell := range header {
+		names[i] = strings.TrimSpace(HeaderName(cell))
+	}
+	return names
+}
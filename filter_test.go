@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterRows(t *testing.T) {
+	rows := [][]string{
+		{"status", "^rpriority", "Due Date", "owner"},
+		{"open", "1", "2024-01-10", "Jane"},
+		{"closed", "2", "2024-02-01", "John"},
+		{"open", "10", "", "张三"},
+		{"open", "1,200", "2024-03-05", ""},
+		{"open", "", "2024-04-01", "Empty"},
+		{"closed", "N/A", "", "Unknown"},
+	}
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected []string // 期望保留的 owner
+	}{
+		{"字符串相等", `status == "open"`, []string{"Jane", "张三", "", "Empty"}},
+		{"数值比较", `priority <= 2`, []string{"Jane", "John"}},
+		{"数值而非字典序", `priority > 2`, []string{"张三", ""}},
+		{"与运算", `status == "open" && priority < 5`, []string{"Jane"}},
+		{"或运算", `owner == "John" || owner == "张三"`, []string{"John", "张三"}},
+		{"非运算和括号", `!(status == "open" || priority == 2)`, []string{"Unknown"}},
+		{"正则匹配", `owner =~ "^J"`, []string{"Jane", "John"}},
+		{"正则不匹配", `owner !~ "^J"`, []string{"张三", "", "Empty", "Unknown"}},
+		{"反引号列名", "`Due Date` >= \"2024-02-01\"", []string{"John", "", "Empty"}},
+		{"非空判断", `owner`, []string{"Jane", "John", "张三", "Empty", "Unknown"}},
+		{"单引号字符串", `status != 'open'`, []string{"John", "Unknown"}},
+		{"空单元格不参与数值比较", `priority < 100`, []string{"Jane", "John", "张三"}},
+		{"非数字单元格不参与数值比较", `priority >= 1`, []string{"Jane", "John", "张三", ""}},
+		{"非数字单元格可以判断相等", `priority == "N/A" || priority != 1 && priority == ""`, []string{"Empty", "Unknown"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FilterRows(rows, tt.expr)
+			if err != nil {
+				t.Fatalf("FilterRows(%q) error = %v", tt.expr, err)
+			}
+			if result[0][0] != "status" {
+				t.Fatalf("表头行丢失: %v", result[0])
+			}
+			var owners []string
+			for _, row := range result[1:] {
+				owners = append(owners, row[3])
+			}
+			if strings.Join(owners, "|") != strings.Join(tt.expected, "|") || len(owners) != len(tt.expected) {
+				t.Errorf("FilterRows(%q) owners = %q, 期望 %q", tt.expr, owners, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	header := []string{"status", "priority"}
+
+	tests := []struct {
+		name    string
+		expr    string
+		wantMsg string
+	}{
+		{"未知列名", `state == "open"`, `unknown column "state" (available: status, priority)`},
+		{"未闭合字符串", `status == "open`, "unterminated"},
+		{"缺少右括号", `(status == "open"`, `expected ")"`},
+		{"正则需要字符串", `status =~ priority`, "expected string pattern"},
+		{"无效正则", `status =~ "("`, "invalid pattern"},
+		{"多余内容", `status == "open" priority`, "unexpected"},
+		{"表达式不完整", `status ==`, "unexpected end"},
+		{"非法字符", `status # 1`, "unexpected character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilter(tt.expr, header)
+			if err == nil {
+				t.Fatalf("ParseFilter(%q) 应返回错误", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("ParseFilter(%q) error = %q, 期望包含 %q", tt.expr, err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
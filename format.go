@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// numberRegex matches plain numeric cells, optionally with an exponent
+	numberRegex = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+	// groupedNumberRegex matches numbers that already use thousands separators
+	groupedNumberRegex = regexp.MustCompile(`^[+-]?\d{1,3}(,\d{3})+(\.\d+)?$`)
+	// numberFormatRegex matches format specs: num, num(2), fixed(2), pct(1)
+	numberFormatRegex = regexp.MustCompile(`^(?i)(num|fixed|pct)(?:\((\d+)\))?$`)
+)
+
+// NumberFormat 数字格式规则
+type NumberFormat struct {
+	Kind     string // "num"（千分位）、"fixed"（固定小数位）或 "pct"（百分比）
+	Decimals int    // 小数位数，-1 表示保留原始精度
+}
+
+// String 返回数字格式的写法，例如 num、num(2)、pct(1)
+func (f NumberFormat) String() string {
+	if f.Decimals < 0 {
+		return f.Kind
+	}
+	return fmt.Sprintf("%s(%d)", f.Kind, f.Decimals)
+}
+
+// ParseNumberFormat 解析单个数字格式，例如 num、num(2)、fixed(2)、pct(1)
+func ParseNumberFormat(spec string) (NumberFormat, error) {
+	matches := numberFormatRegex.FindStringSubmatch(strings.TrimSpace(spec))
+	if matches == nil {
+		return NumberFormat{}, fmt.Errorf("invalid number format %q", spec)
+	}
+
+	format := NumberFormat{Kind: strings.ToLower(matches[1])}
+	if matches[2] != "" {
+		decimals, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return NumberFormat{}, fmt.Errorf("invalid number format %q", spec)
+		}
+		format.Decimals = decimals
+		return format, nil
+	}
+
+	// 未指定小数位时的默认值
+	switch format.Kind {
+	case "num":
+		format.Decimals = -1
+	case "fixed":
+		format.Decimals = 2
+	case "pct":
+		format.Decimals = 0
+	}
+	return format, nil
+}
+
+// ParseNumberFormats 解析按列指定的数字格式列表
+// 格式: "列名:格式,列名:格式"，列名也可以是从 1 开始的列序号
+func ParseNumberFormats(spec string) (map[string]NumberFormat, error) {
+	formats := make(map[string]NumberFormat)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		sep := strings.LastIndex(item, ":")
+		if sep <= 0 {
+			return nil, fmt.Errorf("invalid column format %q, expected column:format", item)
+		}
+		format, err := ParseNumberFormat(item[sep+1:])
+		if err != nil {
+			return nil, err
+		}
+		formats[strings.TrimSpace(item[:sep])] = format
+	}
+	return formats, nil
+}
+
+// FormatNumber 按指定格式格式化单元格中的数字，非数字单元格原样返回
+// 加粗的数字（例如汇总行）格式化后保留加粗标记
+func FormatNumber(cell string, format NumberFormat) string {
+	if inner, ok := unbold(cell); ok {
+		return bold(FormatNumber(inner, format))
+	}
+
+	value, ok := parseNumber(cell)
+	if !ok {
+		return cell
+	}
+
+	var formatted string
+	switch format.Kind {
+	case "pct":
+		formatted = strconv.FormatFloat(value*100, 'f', format.Decimals, 64) + "%"
+	case "fixed":
+		formatted = strconv.FormatFloat(value, 'f', format.Decimals, 64)
+	default:
+		formatted = groupThousands(strconv.FormatFloat(value, 'f', format.Decimals, 64))
+	}
+	return trimNegativeZero(formatted)
+}
+
+// parseNumber 解析数字单元格，支持已带千分位分隔符的数字
+func parseNumber(cell string) (float64, bool) {
+	cell = strings.TrimSpace(cell)
+	if groupedNumberRegex.MatchString(cell) {
+		cell = strings.ReplaceAll(cell, ",", "")
+	}
+	if !numberRegex.MatchString(cell) {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// groupThousands 为数字字符串的整数部分添加千分位分隔符
+func groupThousands(number string) string {
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign = "-"
+		number = number[1:]
+	}
+
+	integer, fraction := number, ""
+	if dot := strings.Index(number, "."); dot >= 0 {
+		integer, fraction = number[:dot], number[dot:]
+	}
+
+	var grouped strings.Builder
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(r)
+	}
+	return sign + grouped.String() + fraction
+}
+
+// trimNegativeZero 去掉四舍五入后为零的负号，例如 "-0.00" -> "0.00"
+func trimNegativeZero(number string) string {
+	if !strings.HasPrefix(number, "-") {
+		return number
+	}
+	if strings.Trim(number, "-0.,%") == "" {
+		return number[1:]
+	}
+	return number
+}
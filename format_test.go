@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseNumberFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected NumberFormat
+		wantErr  bool
+	}{
+		{"千分位", "num", NumberFormat{"num", -1}, false},
+		{"千分位带小数", "num(2)", NumberFormat{"num", 2}, false},
+		{"固定小数默认", "fixed", NumberFormat{"fixed", 2}, false},
+		{"固定小数", "fixed(3)", NumberFormat{"fixed", 3}, false},
+		{"百分比", "pct(1)", NumberFormat{"pct", 1}, false},
+		{"大写", "PCT", NumberFormat{"pct", 0}, false},
+		{"无效格式", "money", NumberFormat{}, true},
+		{"无效小数位", "num(x)", NumberFormat{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseNumberFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNumberFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && result != tt.expected {
+				t.Errorf("ParseNumberFormat(%q) = %+v, 期望 %+v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseNumberFormats(t *testing.T) {
+	formats, err := ParseNumberFormats("Price:num(2), 3:pct")
+	if err != nil {
+		t.Fatalf("ParseNumberFormats() error = %v", err)
+	}
+	if formats["Price"] != (NumberFormat{"num", 2}) {
+		t.Errorf("Price 格式 = %+v", formats["Price"])
+	}
+	if formats["3"] != (NumberFormat{"pct", 0}) {
+		t.Errorf("3 格式 = %+v", formats["3"])
+	}
+
+	if _, err := ParseNumberFormats("Price"); err == nil {
+		t.Error("缺少格式时应返回错误")
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		format   NumberFormat
+		expected string
+	}{
+		{"千分位", "1234567.891", NumberFormat{"num", -1}, "1,234,567.891"},
+		{"千分位四舍五入", "1234567.891", NumberFormat{"num", 2}, "1,234,567.89"},
+		{"千分位整数", "1234567", NumberFormat{"num", 0}, "1,234,567"},
+		{"负数千分位", "-1234.5", NumberFormat{"num", 1}, "-1,234.5"},
+		{"小数字", "123", NumberFormat{"num", -1}, "123"},
+		{"已有千分位", "1,234.5", NumberFormat{"fixed", 2}, "1234.50"},
+		{"固定小数", "3.14159", NumberFormat{"fixed", 2}, "3.14"},
+		{"百分比", "0.1234", NumberFormat{"pct", 1}, "12.3%"},
+		{"百分比整数", "0.5", NumberFormat{"pct", 0}, "50%"},
+		{"负零", "-0.001", NumberFormat{"fixed", 2}, "0.00"},
+		{"科学计数法", "1.5e3", NumberFormat{"num", 0}, "1,500"},
+		{"非数字", "N/A", NumberFormat{"num", 2}, "N/A"},
+		{"货币符号", "$1.50", NumberFormat{"num", 2}, "$1.50"},
+		{"空单元格", "", NumberFormat{"num", 2}, ""},
+		{"无穷大", "Inf", NumberFormat{"num", 2}, "Inf"},
+		{"加粗数字", "**1234.5**", NumberFormat{"num", 2}, "**1,234.50**"},
+		{"加粗文本", "**Total**", NumberFormat{"num", 2}, "**Total**"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatNumber(tt.input, tt.format)
+			if result != tt.expected {
+				t.Errorf("FormatNumber(%q, %+v) = %q, 期望 %q", tt.input, tt.format, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertToMarkdownWithNumberFormats(t *testing.T) {
+	converter := NewConverter()
+	converter.NumberFormats = map[string]NumberFormat{
+		"amount": {"num", 2},
+		"2":      {"pct", 0},
+	}
+
+	rows := [][]string{
+		{"^ramount", "rate"},
+		{"1234567.891", "0.25"},
+	}
+
+	expected := "| amount        | rate  |\n|--------------:|-------|\n| 1,234,567.89  | 25%   |"
+	result := converter.ConvertToMarkdown(rows)
+	if result != expected {
+		t.Errorf("ConvertToMarkdown() = %q, 期望 %q", result, expected)
+	}
+}
@@ -1,3 +1,10 @@
 module github.com/lyuangg/excel-to-markdown
 
 go 1.25.3
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/rivo/uniseg v0.4.7
+	golang.org/x/net v0.57.0
+	golang.org/x/text v0.40.0
+)
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// groupKeySeparator 拼接多列分组键时使用的分隔符，不会出现在普通文本中
+const groupKeySeparator = "\x00"
+
+// GroupRows 按一列或多列分组并计算聚合值，分组按首次出现的顺序排列
+// groupSpec: "region,product"；aggSpec: "cost:sum,id:count"，为空时统计每组行数
+func GroupRows(rows [][]string, groupSpec string, aggSpec string) ([][]string, error) {
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	groupIndexes, err := resolveColumns(rows[0], groupSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var specs []AggregateSpec
+	var aggIndexes []int
+	if aggSpec != "" {
+		specs, err = ParseAggregates(aggSpec)
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range specs {
+			index, err := findColumn(rows[0], spec.Column)
+			if err != nil {
+				return nil, err
+			}
+			aggIndexes = append(aggIndexes, index)
+		}
+	}
+
+	// 按首次出现顺序收集分组
+	var keys []string
+	groups := make(map[string][][]string)
+	for _, row := range rows[1:] {
+		parts := make([]string, len(groupIndexes))
+		for i, index := range groupIndexes {
+			parts[i] = cellAt(row, index)
+		}
+		key := strings.Join(parts, groupKeySeparator)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+
+	header := make([]string, 0, len(groupIndexes)+len(specs))
+	for _, index := range groupIndexes {
+		header = append(header, rows[0][index])
+	}
+	if len(specs) == 0 {
+		header = append(header, "count")
+	}
+	for i, spec := range specs {
+		// 聚合列沿用源列的对齐、数字格式和宽度；计数与源列的单位无关
+		directive, name := ParseHeaderCell(rows[0][aggIndexes[i]])
+		if spec.Func == "count" {
+			directive = HeaderDirective{}
+		}
+		header = append(header, directiveHeader(directive, fmt.Sprintf("%s(%s)", spec.Func, name)))
+	}
+
+	result := [][]string{header}
+	for _, key := range keys {
+		groupRows := groups[key]
+		row := strings.Split(key, groupKeySeparator)
+		if len(specs) == 0 {
+			row = append(row, fmt.Sprint(len(groupRows)))
+		}
+		for i, spec := range specs {
+			row = append(row, Aggregate(spec.Func, columnCells(groupRows, aggIndexes[i])))
+		}
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+// PivotSpec 透视规则
+type PivotSpec struct {
+	Row    string // 作为行的列
+	Column string // 其值展开为新列的列
+	Value  string // 被聚合的列
+	Func   string // 聚合函数，默认 sum
+}
+
+// ParsePivotSpec 解析透视规则，格式: "row=region,col=quarter,value=revenue,agg=sum"
+func ParsePivotSpec(spec string) (PivotSpec, error) {
+	pivot := PivotSpec{Func: "sum"}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return PivotSpec{}, fmt.Errorf("invalid pivot option %q, expected name=value", item)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "row":
+			pivot.Row = value
+		case "col", "column":
+			pivot.Column = value
+		case "value", "val":
+			pivot.Value = value
+		case "agg":
+			pivot.Func = strings.ToLower(value)
+		default:
+			return PivotSpec{}, fmt.Errorf("unknown pivot option %q", name)
+		}
+	}
+
+	if pivot.Row == "" || pivot.Column == "" || pivot.Value == "" {
+		return PivotSpec{}, fmt.Errorf("pivot requires row, col and value")
+	}
+	if _, err := ParseAggregates(pivot.Value + ":" + pivot.Func); err != nil {
+		return PivotSpec{}, err
+	}
+	return pivot, nil
+}
+
+// PivotRows 将长表透视为宽表：行键 × 列键 → 聚合值
+// 行和列都按首次出现的顺序排列，没有数据的单元格为空
+func PivotRows(rows [][]string, spec string) ([][]string, error) {
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	pivot, err := ParsePivotSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	rowIndex, err := findColumn(rows[0], pivot.Row)
+	if err != nil {
+		return nil, err
+	}
+	columnIndex, err := findColumn(rows[0], pivot.Column)
+	if err != nil {
+		return nil, err
+	}
+	valueIndex, err := findColumn(rows[0], pivot.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var rowKeys, columnKeys []string
+	seenRows := make(map[string]bool)
+	seenColumns := make(map[string]bool)
+	cells := make(map[[2]string][]string)
+	for _, row := range rows[1:] {
+		rowKey, columnKey := cellAt(row, rowIndex), cellAt(row, columnIndex)
+		if !seenRows[rowKey] {
+			seenRows[rowKey] = true
+			rowKeys = append(rowKeys, rowKey)
+		}
+		if !seenColumns[columnKey] {
+			seenColumns[columnKey] = true
+			columnKeys = append(columnKeys, columnKey)
+		}
+		key := [2]string{rowKey, columnKey}
+		cells[key] = append(cells[key], cellAt(row, valueIndex))
+	}
+
+	// 透视列沿用值列的对齐、数字格式和宽度，列键是数据，不能被解析为表头指令
+	// 计数与值列的单位无关，不沿用指令
+	valueDirective, _ := ParseHeaderCell(rows[0][valueIndex])
+	if pivot.Func == "count" {
+		valueDirective = HeaderDirective{}
+	}
+	header := []string{rows[0][rowIndex]}
+	for _, columnKey := range columnKeys {
+		header = append(header, directiveHeader(valueDirective, pivotColumnName(columnKey)))
+	}
+	result := [][]string{header}
+	for _, rowKey := range rowKeys {
+		row := []string{rowKey}
+		for _, columnKey := range columnKeys {
+			values, ok := cells[[2]string{rowKey, columnKey}]
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, Aggregate(pivot.Func, values))
+		}
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+// emptyPivotColumn 列键为空时透视列使用的列名
+const emptyPivotColumn = "(empty)"
+
+// pivotColumnName 返回列键对应的透视列名，空列键使用占位列名
+func pivotColumnName(key string) string {
+	if strings.TrimSpace(key) == "" {
+		return emptyPivotColumn
+	}
+	return key
+}
+
+// aggregateSourceRegex matches aggregate column names generated by GroupRows: sum(revenue)
+var aggregateSourceRegex = regexp.MustCompile(`^(?i)(sum|avg|min|max)\((.+)\)$`)
+
+// aggregateSource 返回聚合列的源列名，例如 sum(revenue) 返回 revenue；计数列和普通列返回 false
+func aggregateSource(name string) (string, bool) {
+	matches := aggregateSourceRegex.FindStringSubmatch(name)
+	if matches == nil {
+		return "", false
+	}
+	return matches[2], true
+}
+
+// columnCells 返回多行中指定列的单元格
+func columnCells(rows [][]string, index int) []string {
+	cells := make([]string, 0, len(rows))
+	for _, row := range rows {
+		cells = append(cells, cellAt(row, index))
+	}
+	return cells
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"testing"
+)
+
+var salesRows = [][]string{
+	{"region", "product", "quarter", "revenue"},
+	{"EU", "A", "Q1", "10"},
+	{"US", "A", "Q1", "20"},
+	{"EU", "B", "Q2", "5"},
+	{"EU", "A", "Q1", "1.5"},
+}
+
+func TestGroupRows(t *testing.T) {
+	tests := []struct {
+		name      string
+		groupSpec string
+		aggSpec   string
+		expected  [][]string
+		wantErr   bool
+	}{
+		{
+			"单列分组统计行数",
+			"region",
+			"",
+			[][]string{{"region", "count"}, {"EU", "3"}, {"US", "1"}},
+			false,
+		},
+		{
+			"多列分组和聚合",
+			"region,product",
+			"revenue:sum,revenue:max",
+			[][]string{
+				{"region", "product", "sum(revenue)", "max(revenue)"},
+				{"EU", "A", "11.5", "10.0"},
+				{"US", "A", "20", "20"},
+				{"EU", "B", "5", "5"},
+			},
+			false,
+		},
+		{"未知分组列", "country", "", nil, true},
+		{"未知聚合列", "region", "cost:sum", nil, true},
+		{"无效聚合函数", "region", "revenue:median", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GroupRows(salesRows, tt.groupSpec, tt.aggSpec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GroupRows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !equalRows(result, tt.expected) {
+				t.Errorf("GroupRows() = %v, 期望 %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParsePivotSpec(t *testing.T) {
+	spec, err := ParsePivotSpec("row=region, col=quarter, value=revenue")
+	if err != nil {
+		t.Fatalf("ParsePivotSpec() error = %v", err)
+	}
+	expected := PivotSpec{Row: "region", Column: "quarter", Value: "revenue", Func: "sum"}
+	if spec != expected {
+		t.Errorf("ParsePivotSpec() = %+v, 期望 %+v", spec, expected)
+	}
+
+	for _, input := range []string{"row=region,col=quarter", "row=region,col=quarter,value=revenue,agg=median", "region"} {
+		if _, err := ParsePivotSpec(input); err == nil {
+			t.Errorf("ParsePivotSpec(%q) 应返回错误", input)
+		}
+	}
+}
+
+func TestPivotRows(t *testing.T) {
+	result, err := PivotRows(salesRows, "row=region,col=quarter,value=revenue,agg=sum")
+	if err != nil {
+		t.Fatalf("PivotRows() error = %v", err)
+	}
+	expected := [][]string{
+		{"region", "Q1", "Q2"},
+		{"EU", "11.5", "5"},
+		{"US", "20", ""},
+	}
+	if !equalRows(result, expected) {
+		t.Errorf("PivotRows() = %v, 期望 %v", result, expected)
+	}
+
+	if _, err := PivotRows(salesRows, "row=country,col=quarter,value=revenue"); err == nil {
+		t.Error("未知列名应返回错误")
+	}
+}
+
+func TestGroupRowsKeepsDirectives(t *testing.T) {
+	rows := [][]string{
+		{"^c region", "^r:num(2):w10 revenue"},
+		{"EU", "10"},
+		{"EU", "5"},
+	}
+	result, err := GroupRows(rows, "region", "revenue:sum,revenue:count")
+	if err != nil {
+		t.Fatalf("GroupRows() error = %v", err)
+	}
+	expected := []string{"^c region", "^r:num(2):w10 sum(revenue)", "count(revenue)"}
+	if !equalRows(result[:1], [][]string{expected}) {
+		t.Errorf("GroupRows() 表头 = %q, 期望 %q", result[0], expected)
+	}
+}
+
+func TestPivotRowsHeaderLabels(t *testing.T) {
+	rows := [][]string{
+		{"status", "priority", "^r:fixed(1) hours"},
+		{"open", "", "2"},
+		{"open", "^rhigh", "3"},
+		{"closed", "low", "4"},
+	}
+	result, err := PivotRows(rows, "row=status,col=priority,value=hours")
+	if err != nil {
+		t.Fatalf("PivotRows() error = %v", err)
+	}
+	expected := []string{"status", "^r:fixed(1) (empty)", "^r:fixed(1) ^rhigh", "^r:fixed(1) low"}
+	if !equalRows(result[:1], [][]string{expected}) {
+		t.Errorf("PivotRows() 表头 = %q, 期望 %q", result[0], expected)
+	}
+
+	result, err = PivotRows(rows[:3], "row=status,col=priority,value=hours,agg=count")
+	if err != nil {
+		t.Fatalf("PivotRows() error = %v", err)
+	}
+	expected = []string{"status", "(empty)", "^ ^rhigh"}
+	if !equalRows(result[:1], [][]string{expected}) {
+		t.Errorf("PivotRows() 表头 = %q, 期望 %q", result[0], expected)
+	}
+	for _, cell := range expected {
+		if directive, _ := ParseHeaderCell(cell); directive != (HeaderDirective{}) {
+			t.Errorf("ParseHeaderCell(%q) = %+v, 期望没有指令", cell, directive)
+		}
+	}
+}
+
+func TestConvertToMarkdownWithGroupFormat(t *testing.T) {
+	converter := NewConverter()
+	converter.NumberFormats = map[string]NumberFormat{"revenue": {"num", 2}}
+
+	rows, err := GroupRows(salesRows, "region", "revenue:sum,revenue:count")
+	if err != nil {
+		t.Fatalf("GroupRows() error = %v", err)
+	}
+
+	expected := "| region  | sum(revenue)  | count(revenue)  |\n" +
+		"|---------|---------------|-----------------|\n" +
+		"| EU      | 16.50         | 3               |\n" +
+		"| US      | 20.00         | 1               |"
+	result := converter.ConvertToMarkdown(rows)
+	if result != expected {
+		t.Errorf("ConvertToMarkdown() = %q, 期望 %q", result, expected)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DetectHeader 判断第一行是否为表头
+// 依据：第一行与其余行的类型差异（文字表头下是数字或日期列）以及第一行值的唯一性
+// 没有明显证据时默认第一行是表头，保持原有行为
+func DetectHeader(rows [][]string) bool {
+	if len(rows) < 2 {
+		return true
+	}
+
+	header := rows[0]
+	seen := make(map[string]bool)
+	evidenceAgainst := false
+	for i, cell := range header {
+		cell = strings.TrimSpace(cell)
+		columnType := detectColumnType(rows[1:], i)
+		cellType := detectCellType(cell)
+
+		// 文字表头位于数字或日期列之上，是最强的表头证据
+		if columnType != "" && columnType != "str" && cellType == "str" {
+			return true
+		}
+		// 第一行与下面的数据类型相同
+		if columnType != "" && columnType != "str" && cellType == columnType {
+			evidenceAgainst = true
+		}
+		// 表头通常不为空且不重复
+		if cell == "" || seen[cell] {
+			evidenceAgainst = true
+		}
+		seen[cell] = true
+	}
+	return !evidenceAgainst
+}
+
+// detectColumnType 返回列中非空单元格的共同类型："num"、"date"、"str"，全部为空时返回 ""
+func detectColumnType(rows [][]string, index int) string {
+	columnType := ""
+	for _, row := range rows {
+		cell := cellAt(row, index)
+		if cell == "" {
+			continue
+		}
+		cellType := detectCellType(cell)
+		if columnType == "" {
+			columnType = cellType
+		} else if columnType != cellType {
+			return "str"
+		}
+	}
+	return columnType
+}
+
+// detectCellType 返回单元格的类型："num"、"date" 或 "str"
+func detectCellType(cell string) string {
+	if _, ok := parseNumber(cell); ok {
+		return "num"
+	}
+	if _, ok := parseDate(cell); ok {
+		return "date"
+	}
+	return "str"
+}
+
+// SyntheticHeader 生成表头：style 为 "letter" 时使用电子表格风格 A, B, ..., Z, AA，
+// 否则使用 Column 1..N
+func SyntheticHeader(columns int, style string) []string {
+	header := make([]string, columns)
+	for i := range header {
+		if style == "letter" {
+			header[i] = columnLetter(i)
+		} else {
+			header[i] = fmt.Sprintf("Column %d", i+1)
+		}
+	}
+	return header
+}
+
+// AddSyntheticHeader 在没有表头的数据前插入生成的表头，列数取最长的行
+func AddSyntheticHeader(rows [][]string, style string) [][]string {
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	return append([][]string{SyntheticHeader(columns, style)}, rows...)
+}
+
+// columnLetter 返回从 0 开始的列序号对应的电子表格列名：0 -> A, 25 -> Z, 26 -> AA
+func columnLetter(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
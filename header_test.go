@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestDetectHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    [][]string
+		expected bool
+	}{
+		{"文字表头和数字数据", [][]string{{"Name", "Age"}, {"Jane", "30"}, {"John", "25"}}, true},
+		{"日期列表头", [][]string{{"Task", "Due"}, {"A", "2024-01-02"}}, true},
+		{"全是文字", [][]string{{"Name", "Title"}, {"Jane", "CEO"}}, true},
+		{"全是数字", [][]string{{"1", "2"}, {"3", "4"}}, false},
+		{"第一行是日期", [][]string{{"Jane", "2024-01-01"}, {"John", "2024-02-01"}}, false},
+		{"第一行有重复值", [][]string{{"a", "a"}, {"b", "c"}}, false},
+		{"第一行有空值", [][]string{{"Name", ""}, {"Jane", "CEO"}}, false},
+		{"只有一行", [][]string{{"1", "2"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DetectHeader(tt.input)
+			if result != tt.expected {
+				t.Errorf("DetectHeader(%v) = %v, 期望 %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSyntheticHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		columns  int
+		style    string
+		expected []string
+	}{
+		{"列序号", 3, "column", []string{"Column 1", "Column 2", "Column 3"}},
+		{"字母", 3, "letter", []string{"A", "B", "C"}},
+		{"无列", 0, "letter", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SyntheticHeader(tt.columns, tt.style)
+			if !equalRows([][]string{result}, [][]string{tt.expected}) {
+				t.Errorf("SyntheticHeader(%d, %q) = %v, 期望 %v", tt.columns, tt.style, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestColumnLetter(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for index, expected := range tests {
+		if result := columnLetter(index); result != expected {
+			t.Errorf("columnLetter(%d) = %q, 期望 %q", index, result, expected)
+		}
+	}
+}
+
+func TestAddSyntheticHeader(t *testing.T) {
+	rows := [][]string{{"1"}, {"2", "3"}}
+	result := AddSyntheticHeader(rows, "column")
+	expected := [][]string{{"Column 1", "Column 2"}, {"1"}, {"2", "3"}}
+	if !equalRows(result, expected) {
+		t.Errorf("AddSyntheticHeader() = %v, 期望 %v", result, expected)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// lineBreakMarker 解析时代表 <br> 的占位符，整理空白后替换为 "<br>"
+const lineBreakMarker = "\x00"
+
+// isHTMLTable 检查数据是否为包含 <table> 的 HTML 片段（例如从浏览器或 Excel 复制的 text/html 内容）
+func isHTMLTable(data string) bool {
+	data = strings.TrimSpace(strings.TrimPrefix(data, "\uFEFF"))
+	return strings.HasPrefix(data, "<") && strings.Contains(strings.ToLower(data), "<table")
+}
+
+// htmlTableBuilder 逐个接收单元格并展开合并单元格
+// colspan 合并的单元格只在第一列保留内容，其余列为空；rowspan 合并的单元格在下面的行中同样留空
+type htmlTableBuilder struct {
+	rows    [][]string
+	row     []string
+	inRow   bool
+	cell    *strings.Builder
+	colspan int
+	rowspan int
+	// pending 每列还需要为上方的 rowspan 单元格留空的行数
+	pending []int
+}
+
+// startRow 开始新的一行，未关闭的行和单元格先结束
+func (b *htmlTableBuilder) startRow() {
+	b.endRow()
+	b.row = nil
+	b.inRow = true
+}
+
+// endRow 结束当前行，为行尾被 rowspan 占用的列填充空单元格
+func (b *htmlTableBuilder) endRow() {
+	b.endCell()
+	if !b.inRow {
+		return
+	}
+	for i := len(b.row); i < len(b.pending); i++ {
+		if b.pending[i] > 0 {
+			b.pending[i]--
+			for len(b.row) <= i {
+				b.row = append(b.row, "")
+			}
+		}
+	}
+	b.rows = append(b.rows, b.row)
+	b.inRow = false
+}
+
+// startCell 开始新的单元格，不在 <tr> 中的单元格视为新的一行
+func (b *htmlTableBuilder) startCell(token html.Token) {
+	b.endCell()
+	if !b.inRow {
+		b.startRow()
+	}
+	b.cell = &strings.Builder{}
+	b.colspan = spanAttr(token, "colspan")
+	b.rowspan = spanAttr(token, "rowspan")
+}
+
+// endCell 结束当前单元格并写入行中
+func (b *htmlTableBuilder) endCell() {
+	if b.cell == nil {
+		return
+	}
+	b.skipSpanned()
+	column := len(b.row)
+	b.row = append(b.row, cleanHTMLCell(b.cell.String()))
+	for range b.colspan - 1 {
+		b.row = append(b.row, "")
+	}
+	for len(b.pending) < len(b.row) {
+		b.pending = append(b.pending, 0)
+	}
+	for i := column; i < len(b.row); i++ {
+		b.pending[i] = b.rowspan - 1
+	}
+	b.cell = nil
+}
+
+// skipSpanned 为被上方 rowspan 单元格占用的列填充空单元格，直到遇到未被占用的列
+func (b *htmlTableBuilder) skipSpanned() {
+	for len(b.row) < len(b.pending) && b.pending[len(b.row)] > 0 {
+		b.pending[len(b.row)]--
+		b.row = append(b.row, "")
+	}
+}
+
+// spanAttr 读取 colspan 或 rowspan 属性，缺失或无效时返回 1
+func spanAttr(token html.Token, name string) int {
+	for _, attr := range token.Attr {
+		if attr.Key == name {
+			if n, err := strconv.Atoi(strings.TrimSpace(attr.Val)); err == nil && n > 1 {
+				return min(n, 1000)
+			}
+		}
+	}
+	return 1
+}
+
+// cleanHTMLCell 合并单元格中的连续空白，<br> 转为 Markdown 表格中可用的 "<br>"，
+// 去掉开头和结尾的空行
+func cleanHTMLCell(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, lineBreakMarker) {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "<br>")
+}
+
+// ParseHTMLTable 解析 HTML 片段中的第一个表格
+// <th> 和 <td> 都作为单元格，合并单元格展开为空单元格，嵌套表格的文字并入所在的单元格
+func ParseHTMLTable(data string) ([][]string, error) {
+	tokenizer := html.NewTokenizer(strings.NewReader(data))
+	builder := &htmlTableBuilder{}
+	depth := 0
+	for {
+		if tokenizer.Next() == html.ErrorToken {
+			if err := tokenizer.Err(); err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+		token := tokenizer.Token()
+		switch token.Type {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch {
+			case token.DataAtom == atom.Table:
+				depth++
+			case token.DataAtom == atom.Br:
+				if depth > 0 && builder.cell != nil {
+					builder.cell.WriteString(lineBreakMarker)
+				}
+			case depth != 1:
+			case token.DataAtom == atom.Tr:
+				builder.startRow()
+			case token.DataAtom == atom.Td || token.DataAtom == atom.Th:
+				builder.startCell(token)
+			}
+		case html.EndTagToken:
+			switch {
+			case token.DataAtom == atom.Table && depth > 0:
+				depth--
+				if depth == 0 {
+					builder.endRow()
+					return builder.rows, nil
+				}
+			case depth != 1:
+			case token.DataAtom == atom.Tr:
+				builder.endRow()
+			case token.DataAtom == atom.Td || token.DataAtom == atom.Th:
+				builder.endCell()
+			}
+		case html.TextToken:
+			if depth > 0 && builder.cell != nil {
+				builder.cell.WriteString(token.Data)
+			}
+		}
+	}
+	if depth == 0 {
+		return nil, errors.New("no <table> found in HTML")
+	}
+	// 没有 </table> 的片段按已读到的内容处理
+	builder.endRow()
+	return builder.rows, nil
+}
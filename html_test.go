@@ -0,0 +1,145 @@
+package main
+
+import "testing"
+
+func TestIsHTMLTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"表格片段", "<table><tr><td>a</td></tr></table>", true},
+		{"带 meta 和 BOM", "\uFEFF<meta charset='utf-8'><TABLE><tr><td>a</td></tr></TABLE>", true},
+		{"开头有空白", "\n  <html><body><table></table></body></html>", true},
+		{"没有表格的 HTML", "<p>hello</p>", false},
+		{"TSV 中出现 <table", "a\t<table>", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isHTMLTable(tt.input); result != tt.expected {
+				t.Errorf("isHTMLTable(%q) = %v, 期望 %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseHTMLTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected [][]string
+		wantErr  bool
+	}{
+		{
+			"表头和数据行",
+			"<table><thead><tr><th>Name</th><th>Title</th></tr></thead><tbody><tr><td>Jane</td><td>CEO</td></tr></tbody></table>",
+			[][]string{{"Name", "Title"}, {"Jane", "CEO"}},
+			false,
+		},
+		{
+			"合并空白和实体",
+			"<table><tr><td>\n  San\n  Francisco </td><td>A &amp; B&nbsp;C</td></tr></table>",
+			[][]string{{"San Francisco", "A & B C"}},
+			false,
+		},
+		{
+			"单元格内换行",
+			"<table><tr><td>Line 1<br>Line 2<br/></td><td><br>x</td></tr></table>",
+			[][]string{{"Line 1<br>Line 2", "x"}},
+			false,
+		},
+		{
+			"保留行内标签的文字",
+			"<table><tr><td><b>Bold</b> <a href=\"#\">link</a></td></tr></table>",
+			[][]string{{"Bold link"}},
+			false,
+		},
+		{
+			"colspan",
+			"<table><tr><td colspan=\"2\">Total</td><td>3</td></tr><tr><td>a</td><td>b</td><td>c</td></tr></table>",
+			[][]string{{"Total", "", "3"}, {"a", "b", "c"}},
+			false,
+		},
+		{
+			"rowspan",
+			"<table><tr><td rowspan=\"2\">East</td><td>Q1</td></tr><tr><td>Q2</td></tr><tr><td>West</td><td>Q1</td></tr></table>",
+			[][]string{{"East", "Q1"}, {"", "Q2"}, {"West", "Q1"}},
+			false,
+		},
+		{
+			"行尾的 rowspan",
+			"<table><tr><td>a</td><td rowspan=\"2\">x</td></tr><tr><td>b</td></tr></table>",
+			[][]string{{"a", "x"}, {"b", ""}},
+			false,
+		},
+		{
+			"省略结束标签",
+			"<table><tr><td>a<td>b<tr><td>c<td>d</table>",
+			[][]string{{"a", "b"}, {"c", "d"}},
+			false,
+		},
+		{
+			"只解析第一个表格，忽略表格外的文字",
+			"<p>Title</p><table><caption>Caption</caption><tr><td>a</td></tr></table><table><tr><td>b</td></tr></table>",
+			[][]string{{"a"}},
+			false,
+		},
+		{
+			"嵌套表格的文字并入单元格",
+			"<table><tr><td>outer <table><tr><td>inner</td></tr></table></td><td>b</td></tr></table>",
+			[][]string{{"outer inner", "b"}},
+			false,
+		},
+		{
+			"没有表格",
+			"<p>hello</p>",
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseHTMLTable(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHTMLTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !equalRows(result, tt.expected) {
+				t.Errorf("ParseHTMLTable(%q) = %q, 期望 %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTableHTML(t *testing.T) {
+	input := "<meta charset=\"utf-8\"><table><tr><th>Name</th><th>Title</th></tr><tr><td>Jane</td><td>CEO</td></tr></table>"
+	converter := &Converter{HTML: true}
+	if format := converter.DetectFormat(input); format != "html" {
+		t.Errorf("DetectFormat() = %s, 期望 html", format)
+	}
+	rows, err := converter.ParseTable(input)
+	if err != nil {
+		t.Fatalf("ParseTable() error = %v", err)
+	}
+	if expected := [][]string{{"Name", "Title"}, {"Jane", "CEO"}}; !equalRows(rows, expected) {
+		t.Errorf("ParseTable() = %q, 期望 %q", rows, expected)
+	}
+	// 没有 HTML 内容时按普通格式解析
+	if format := converter.DetectFormat("<Name>\tTitle"); format != "tsv" {
+		t.Errorf("DetectFormat() = %s, 期望 tsv", format)
+	}
+}
+
+func TestConvertTableHTMLRequiresOption(t *testing.T) {
+	input := "<table><tr><td>a</td><td>b</td></tr></table>"
+	if _, err := convertTable(input, options{}, "en"); err == nil {
+		t.Error("convertTable() 未使用 -html 时期望拒绝 HTML 输入")
+	}
+	if _, err := convertTable(input, options{fromClipboard: true, html: true}, "en"); err != nil {
+		t.Errorf("convertTable() error = %v", err)
+	}
+	if err := validateOptions(options{style: "padded", headerStyle: "column", html: true}, "en"); err == nil {
+		t.Error("validateOptions() 期望拒绝没有 -clipboard 的 -html")
+	}
+}
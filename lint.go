@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// LintIssue Markdown 表格中的一个问题
+type LintIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`   // 行号（从 1 开始）
+	Column  int    `json:"column"` // 列号（从 1 开始，按字节计算）
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String 返回 file:line:col: message (rule) 格式的诊断信息
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s (%s)", i.File, i.Line, i.Column, i.Message, i.Rule)
+}
+
+// LintMarkdown 检查文档中的 Markdown 表格，返回按位置排序的问题列表
+// 检查项：
+//   - cell-count: 行的单元格数与表头不一致
+//   - missing-separator: 连续的表格行缺少分隔行（不会被渲染为表格）
+//   - unescaped-pipe: 行内代码中未转义的管道符（GFM 中仍会拆分单元格）
+//   - empty-header: 表头单元格为空
+//   - duplicate-header: 表头列名重复
+//   - trailing-whitespace: 表格行末尾有空白字符
+func LintMarkdown(file string, lines []string) []LintIssue {
+	var issues []LintIssue
+	report := func(line, column int, rule, format string, args ...any) {
+		issues = append(issues, LintIssue{File: file, Line: line + 1, Column: column, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	inTable := make([]bool, len(lines))
+	for _, table := range FindMarkdownTables(lines) {
+		for i := table.StartLine; i < table.EndLine; i++ {
+			inTable[i] = true
+		}
+
+		header := splitTableCells(lines[table.StartLine])
+		seen := make(map[string]bool)
+		for _, cell := range header {
+			switch {
+			case cell.Text == "":
+				report(table.StartLine, cell.Column, "empty-header", "empty header cell")
+			case seen[cell.Text]:
+				report(table.StartLine, cell.Column, "duplicate-header", "duplicate header %q", cell.Text)
+			}
+			seen[cell.Text] = true
+		}
+
+		for i := table.StartLine; i < table.EndLine; i++ {
+			line := lines[i]
+			if i > table.StartLine+1 {
+				if cells := splitTableCells(line); len(cells) != len(header) {
+					column := cells[min(len(cells)-1, len(header))].Column
+					report(i, column, "cell-count", "row has %d cells, expected %d", len(cells), len(header))
+				}
+			}
+			for _, column := range codeSpanPipes(line) {
+				report(i, column, "unescaped-pipe", "unescaped | inside code span splits the cell, use \\|")
+			}
+			if trimmed := strings.TrimRightFunc(line, unicode.IsSpace); len(trimmed) < len(line) {
+				report(i, len(trimmed)+1, "trailing-whitespace", "trailing whitespace")
+			}
+		}
+	}
+
+	// 查找看起来像表格但没有被识别为表格的连续行
+	inCode := codeBlockLines(lines)
+	for i := 0; i+1 < len(lines); i++ {
+		if inTable[i] || inCode[i] || !looksLikeTableRow(lines[i]) || inTable[i+1] || !looksLikeTableRow(lines[i+1]) {
+			continue
+		}
+		header := splitTableCells(lines[i])
+		if alignments, ok := parseSeparatorRow(lines[i+1]); ok {
+			report(i+1, 1, "cell-count", "separator row has %d cells, header has %d", len(alignments), len(header))
+		} else {
+			report(i, 1, "missing-separator", "table rows without a separator row after the header")
+		}
+		for i+1 < len(lines) && looksLikeTableRow(lines[i+1]) {
+			i++
+		}
+	}
+
+	sort.SliceStable(issues, func(a, b int) bool {
+		if issues[a].Line != issues[b].Line {
+			return issues[a].Line < issues[b].Line
+		}
+		return issues[a].Column < issues[b].Column
+	})
+	return issues
+}
+
+// looksLikeTableRow 判断一行是否以管道符开头，像是表格行
+func looksLikeTableRow(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+// codeSpanPipes 返回行内代码（反引号之间）中未转义管道符的列号（从 1 开始）
+func codeSpanPipes(line string) []int {
+	var columns []int
+	for i := 0; i < len(line); {
+		switch line[i] {
+		case '\\':
+			i += 2
+			continue
+		case '`':
+		default:
+			i++
+			continue
+		}
+
+		// 行内代码以相同长度的反引号串结束
+		run := backtickRun(line[i:])
+		end := -1
+		for j := i + run; j < len(line); {
+			if line[j] != '`' {
+				j++
+				continue
+			}
+			r := backtickRun(line[j:])
+			if r == run {
+				end = j
+				break
+			}
+			j += r
+		}
+		if end < 0 {
+			i += run
+			continue
+		}
+		for j := i + run; j < end; j++ {
+			if line[j] == '|' && line[j-1] != '\\' {
+				columns = append(columns, j+1)
+			}
+		}
+		i = end + run
+	}
+	return columns
+}
+
+// backtickRun 返回字符串开头连续反引号的数量
+func backtickRun(s string) int {
+	return len(s) - len(strings.TrimLeft(s, "`"))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		expected []string
+	}{
+		{
+			"没有问题",
+			"| a | b |\n|---|---|\n| 1 | 2 |",
+			nil,
+		},
+		{
+			"单元格数量不一致",
+			"| a | b |\n|---|---|\n| 1 |\n| 1 | 2 | 3 |",
+			[]string{"doc.md:3:3: row has 1 cells, expected 2 (cell-count)", "doc.md:4:11: row has 3 cells, expected 2 (cell-count)"},
+		},
+		{
+			"缺少分隔行",
+			"text\n| a | b |\n| 1 | 2 |\n| 3 | 4 |",
+			[]string{"doc.md:2:1: table rows without a separator row after the header (missing-separator)"},
+		},
+		{
+			"分隔行单元格数量不一致",
+			"| a | b |\n|---|\n| 1 | 2 |",
+			[]string{"doc.md:2:1: separator row has 1 cells, header has 2 (cell-count)"},
+		},
+		{
+			"行内代码中未转义的管道符",
+			"| cmd | note |\n|---|---|\n| `a \\| b` | `x|y` |",
+			[]string{
+				"doc.md:3:16: unescaped | inside code span splits the cell, use \\| (unescaped-pipe)",
+				"doc.md:3:17: row has 3 cells, expected 2 (cell-count)",
+			},
+		},
+		{
+			"空表头和重复表头",
+			"| a |  | a |\n|---|---|---|",
+			[]string{"doc.md:1:6: empty header cell (empty-header)", "doc.md:1:10: duplicate header \"a\" (duplicate-header)"},
+		},
+		{
+			"末尾空白",
+			"| a |  \n|---|\n| 1 |\t",
+			[]string{"doc.md:1:6: trailing whitespace (trailing-whitespace)", "doc.md:3:6: trailing whitespace (trailing-whitespace)"},
+		},
+		{
+			"跳过代码块",
+			"```\n| a |\n| b |\n```",
+			nil,
+		},
+		{
+			"跳过缩进代码块",
+			"    | a |\n    | b |",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []string
+			for _, issue := range LintMarkdown("doc.md", strings.Split(tt.doc, "\n")) {
+				result = append(result, issue.String())
+			}
+			if strings.Join(result, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("LintMarkdown() =\n%s\n期望\n%s", strings.Join(result, "\n"), strings.Join(tt.expected, "\n"))
+			}
+		})
+	}
+}
+
+func TestCodeSpanPipes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{"没有行内代码", "| a | b |", nil},
+		{"转义的管道符", "| `a \\| b` |", nil},
+		{"未转义的管道符", "| `a|b` |", []int{5}},
+		{"双反引号", "| ``a ` |`` |", []int{9}},
+		{"未闭合的反引号", "| `a | b |", nil},
+		{"转义的反引号", "| \\`a | b` |", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := codeSpanPipes(tt.input)
+			if len(result) != len(tt.expected) || (len(result) > 0 && result[0] != tt.expected[0]) {
+				t.Errorf("codeSpanPipes(%q) = %v, 期望 %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -1,9 +1,9 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
@@ -65,29 +65,48 @@ func printErrorf(lang, zhMsg, enMsg string, args ...interface{}) {
 	os.Exit(1)
 }
 
-// readInput reads input from clipboard or stdin
-func readInput(fromClipboard bool, lang string) string {
+// errorf returns a formatted error based on language
+func errorf(lang, zhMsg, enMsg string, args ...interface{}) error {
+	return fmt.Errorf(errorMsg(lang, zhMsg, enMsg), args...)
+}
+
+// exitOnError prints the error and exits if err is not nil
+func exitOnError(lang string, err error) {
+	if err != nil {
+		printErrorf(lang, "错误: %v", "Error: %v", err)
+	}
+}
+
+// readInput reads input from clipboard or stdin and converts it to UTF-8.
+// mimeTypes are the clipboard types to request, in order of preference
+func readInput(fromClipboard bool, mimeTypes []string, encodingName string, lang string) string {
+	var data []byte
 	if fromClipboard {
-		input, err := readFromClipboard()
+		input, err := readFromClipboard(mimeTypes)
 		if err != nil {
 			printError(lang,
 				"无法从剪贴板读取: "+err.Error()+"\n请使用标准输入或安装剪贴板工具",
 				"Failed to read from clipboard: "+err.Error()+"\nPlease use stdin or install clipboard tools")
 		}
-		return input
+		data = []byte(input)
+	} else {
+		// Read from stdin
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			printErrorf(lang, "读取输入时出错: %v", "Error reading input: %v", err)
+		}
+		data = input
 	}
+	return decodeInput(data, encodingName, lang)
+}
 
-	// Read from stdin
-	// Go's slice will automatically grow as needed, starting with a small capacity
-	scanner := bufio.NewScanner(os.Stdin)
-	var lines []string
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-	}
-	if err := scanner.Err(); err != nil {
-		printErrorf(lang, "读取输入时出错: %v", "Error reading input: %v", err)
+// decodeInput converts raw input to UTF-8 and trims trailing line breaks
+func decodeInput(data []byte, encodingName string, lang string) string {
+	text, err := DecodeInput(data, encodingName)
+	if err != nil {
+		printErrorf(lang, "错误: 无法转换输入编码: %v", "Error: Failed to decode input: %v", err)
 	}
-	return strings.Join(lines, "\n")
+	return strings.TrimRight(text, "\r\n")
 }
 
 // validateInput validates that input is not empty
@@ -99,24 +118,6 @@ func validateInput(input string, lang string) {
 	}
 }
 
-// convertTable converts input table data to markdown
-func convertTable(input string, lang string) string {
-	if !looksLikeTable(input) {
-		printError(lang, "输入数据不是表格格式", "Input data is not in table format")
-	}
-
-	converter := NewConverter()
-	rows, err := converter.ParseTable(input)
-	if err != nil {
-		printErrorf(lang, "错误: 解析表格数据失败: %v", "Error: Failed to parse table data: %v", err)
-	}
-	if len(rows) == 0 {
-		printError(lang, "错误: 无法解析表格数据", "Error: Unable to parse table data")
-	}
-
-	return converter.ConvertToMarkdown(rows)
-}
-
 // outputResult outputs markdown to clipboard or stdout
 func outputResult(markdown string, shouldCopy, fromClipboard bool, lang string) {
 	if !shouldCopy {
@@ -142,17 +143,30 @@ func outputResult(markdown string, shouldCopy, fromClipboard bool, lang string)
 	}
 }
 
-// setupUsage sets up custom usage information with bilingual support
-func setupUsage() {
-	lang := getLanguage()
+// setupUsage sets up custom usage information with bilingual support.
+// The convert command gets the full help; other subcommands get their usage line and summary
+func setupUsage(flags *flag.FlagSet, lang string) {
+	if cmd, ok := findCommand(flags.Name()); ok && cmd.name != "convert" {
+		flags.Usage = func() {
+			fmt.Fprintf(os.Stderr, errorMsg(lang, "用法: %s %s %s\n\n", "Usage: %s %s %s\n\n"),
+				os.Args[0], cmd.name, errorMsg(lang, cmd.zhUsage, cmd.enUsage))
+			fmt.Fprintf(os.Stderr, "%s\n\n", errorMsg(lang, cmd.zhSummary, cmd.enSummary))
+			fmt.Fprintf(os.Stderr, "%s\n", errorMsg(lang, "选项:", "Options:"))
+			flags.PrintDefaults()
+		}
+		return
+	}
 
-	flag.Usage = func() {
+	flags.Usage = func() {
 		if lang == "zh" {
 			// Chinese help
-			fmt.Fprintf(os.Stderr, "用法: %s [选项]\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "用法: %s [命令] [选项] [文件...]\n\n", os.Args[0])
 			fmt.Fprintf(os.Stderr, "将 CSV、TSV 或 Column（空格对齐）格式的表格数据转换为 Markdown 表格格式。\n\n")
+			fmt.Fprintf(os.Stderr, "命令:\n")
+			printCommands(lang)
+			fmt.Fprintf(os.Stderr, "\n使用 \"%s help <命令>\" 查看命令的选项。不指定命令时执行 convert。\n\n", os.Args[0])
 			fmt.Fprintf(os.Stderr, "选项:\n")
-			flag.PrintDefaults()
+			flags.PrintDefaults()
 			fmt.Fprintf(os.Stderr, "\n示例:\n")
 			fmt.Fprintf(os.Stderr, "  # 从标准输入读取并输出到终端\n")
 			fmt.Fprintf(os.Stderr, "  echo -e \"Name\\tTitle\\nJane\\tCEO\" | %s\n\n", os.Args[0])
@@ -160,6 +174,16 @@ func setupUsage() {
 			fmt.Fprintf(os.Stderr, "  %s -clipboard\n\n", os.Args[0])
 			fmt.Fprintf(os.Stderr, "  # 从标准输入读取并写入剪贴板\n")
 			fmt.Fprintf(os.Stderr, "  cat data.csv | %s -copy\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  # 转换多个文件，每个文件一个标题\n")
+			fmt.Fprintf(os.Stderr, "  %s a.csv b.tsv > tables.md\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  # 为每个文件生成单独的 .md 文件\n")
+			fmt.Fprintf(os.Stderr, "  %s -outdir docs 'data/*.csv'\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  # 格式化 Markdown 文件中的所有表格\n")
+			fmt.Fprintf(os.Stderr, "  %s fmt -w README.md\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  # 检查 Markdown 文件中的表格\n")
+			fmt.Fprintf(os.Stderr, "  %s lint README.md\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  # 将 Markdown 表格转换回 TSV 并复制，便于粘贴到 Excel\n")
+			fmt.Fprintf(os.Stderr, "  %s reverse -clipboard\n\n", os.Args[0])
 			fmt.Fprintf(os.Stderr, "  # 转换 column 命令对齐的表格\n")
 			fmt.Fprintf(os.Stderr, "  printf \"Name    Age    City\\nJohn    25     NYC\\n\" | %s\n\n", os.Args[0])
 			fmt.Fprintf(os.Stderr, "支持的格式:\n")
@@ -168,14 +192,18 @@ func setupUsage() {
 			fmt.Fprintf(os.Stderr, "  - Column (空格对齐): 支持 column 命令对齐后的输出格式\n\n")
 			fmt.Fprintf(os.Stderr, "列对齐标记:\n")
 			fmt.Fprintf(os.Stderr, "  在表头使用 ^l (左对齐), ^c (居中), ^r (右对齐)\n")
-			fmt.Fprintf(os.Stderr, "  例如: \"^r价格\" 表示右对齐的价格列\n\n")
+			fmt.Fprintf(os.Stderr, "  例如: \"^r价格\" 表示右对齐的价格列\n")
+			fmt.Fprintf(os.Stderr, "  扩展指令: \"^r:num(2):w30:hide:as(新列名) 列名\"\n\n")
 			fmt.Fprintf(os.Stderr, "更多信息请查看: https://github.com/lyuangg/excel-to-markdown\n")
 		} else {
 			// English help
-			fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "Usage: %s [command] [options] [file...]\n\n", os.Args[0])
 			fmt.Fprintf(os.Stderr, "Convert CSV, TSV, or Column (space-aligned) table data to Markdown table format.\n\n")
+			fmt.Fprintf(os.Stderr, "Commands:\n")
+			printCommands(lang)
+			fmt.Fprintf(os.Stderr, "\nRun \"%s help <command>\" for the options of a command. Without a command, convert is run.\n\n", os.Args[0])
 			fmt.Fprintf(os.Stderr, "Options:\n")
-			flag.PrintDefaults()
+			flags.PrintDefaults()
 			fmt.Fprintf(os.Stderr, "\nExamples:\n")
 			fmt.Fprintf(os.Stderr, "  # Read from stdin and output to terminal\n")
 			fmt.Fprintf(os.Stderr, "  echo -e \"Name\\tTitle\\nJane\\tCEO\" | %s\n\n", os.Args[0])
@@ -183,6 +211,16 @@ func setupUsage() {
 			fmt.Fprintf(os.Stderr, "  %s -clipboard\n\n", os.Args[0])
 			fmt.Fprintf(os.Stderr, "  # Read from stdin and write to clipboard\n")
 			fmt.Fprintf(os.Stderr, "  cat data.csv | %s -copy\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  # Convert several files, with a heading per file\n")
+			fmt.Fprintf(os.Stderr, "  %s a.csv b.tsv > tables.md\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  # Write one .md file per input file\n")
+			fmt.Fprintf(os.Stderr, "  %s -outdir docs 'data/*.csv'\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  # Format every table in a Markdown file\n")
+			fmt.Fprintf(os.Stderr, "  %s fmt -w README.md\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  # Check the tables in a Markdown file\n")
+			fmt.Fprintf(os.Stderr, "  %s lint README.md\n\n", os.Args[0])
+			fmt.Fprintf(os.Stderr, "  # Turn a Markdown table back into TSV on the clipboard, ready to paste into Excel\n")
+			fmt.Fprintf(os.Stderr, "  %s reverse -clipboard\n\n", os.Args[0])
 			fmt.Fprintf(os.Stderr, "  # Convert column command aligned table\n")
 			fmt.Fprintf(os.Stderr, "  printf \"Name    Age    City\\nJohn    25     NYC\\n\" | %s\n\n", os.Args[0])
 			fmt.Fprintf(os.Stderr, "Supported formats:\n")
@@ -191,41 +229,111 @@ func setupUsage() {
 			fmt.Fprintf(os.Stderr, "  - Column (space-aligned): Supports column command aligned output format\n\n")
 			fmt.Fprintf(os.Stderr, "Column alignment markers:\n")
 			fmt.Fprintf(os.Stderr, "  Use ^l (left), ^c (center), ^r (right) in header row\n")
-			fmt.Fprintf(os.Stderr, "  Example: \"^rPrice\" for right-aligned price column\n\n")
+			fmt.Fprintf(os.Stderr, "  Example: \"^rPrice\" for right-aligned price column\n")
+			fmt.Fprintf(os.Stderr, "  Extended directives: \"^r:num(2):w30:hide:as(New Name) Column\"\n\n")
 			fmt.Fprintf(os.Stderr, "For more information, visit: https://github.com/lyuangg/excel-to-markdown\n")
 		}
 	}
 }
 
-func main() {
-	lang := getLanguage()
+// command is a subcommand with its bilingual usage and summary
+type command struct {
+	name      string
+	zhUsage   string
+	enUsage   string
+	zhSummary string
+	enSummary string
+	run       func(args []string, lang string)
+}
+
+// commandList returns the available subcommands
+func commandList() []command {
+	return []command{
+		{"convert", "[选项] [文件...]", "[options] [file...]",
+			"将 CSV、TSV 或 Column 表格数据转换为 Markdown 表格（默认命令）",
+			"Convert CSV, TSV or Column table data to a Markdown table (default command)", runConvert},
+		{"fmt", "[-w] [-l] [文件...]", "[-w] [-l] [file...]",
+			"重新格式化 Markdown 文件中的所有表格，其余内容保持不变",
+			"Reformat every table in Markdown files, leaving all other text unchanged", runFmt},
+		{"lint", "[-json] [文件...]", "[-json] [file...]",
+			"检查 Markdown 文件中的表格问题",
+			"Check tables in Markdown files for problems", runLint},
+		{"reverse", "[选项] [文件]", "[options] [file]",
+			"将 Markdown 表格转换回 TSV 或 CSV",
+			"Convert a Markdown table back to TSV or CSV", runReverse},
+		{"serve", "[-addr 地址] [转换选项]", "[-addr host:port] [conversion options]",
+			"启动 HTTP 服务，转换 POST 到 /convert 的表格数据",
+			"Start an HTTP server that converts table data posted to /convert", runServe},
+		{"watch", "[-interval 时间] [转换选项]", "[-interval duration] [conversion options]",
+			"监视剪贴板，将复制的表格自动转换为 Markdown",
+			"Watch the clipboard and convert copied tables to Markdown", runWatch},
+	}
+}
 
-	// Set flag descriptions based on language
-	clipboardDesc := errorMsg(lang, "从剪贴板读取数据（跨平台支持）", "Read data from clipboard (cross-platform support)")
-	copyDesc := errorMsg(lang, "将结果写入剪贴板（跨平台支持）", "Write result to clipboard (cross-platform support)")
+// findCommand looks up a subcommand by name
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commandList() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
 
-	fromClipboard := flag.Bool("clipboard", false, clipboardDesc)
-	toClipboard := flag.Bool("copy", false, copyDesc)
-	setupUsage()
-	flag.Parse()
+// printCommands prints the subcommand list for the main help
+func printCommands(lang string) {
+	for _, cmd := range commandList() {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, errorMsg(lang, cmd.zhSummary, cmd.enSummary))
+	}
+}
 
-	// Read and validate input
-	input := readInput(*fromClipboard, lang)
-	validateInput(input, lang)
+// runHelp prints the help of a subcommand, or the main help without arguments
+func runHelp(args []string, lang string) {
+	if len(args) == 0 {
+		args = []string{"convert"}
+	}
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		printErrorf(lang, "错误: 未知命令: %s", "Error: Unknown command: %s", args[0])
+	}
+	cmd.run([]string{"-h"}, lang)
+}
 
-	// Convert table to markdown
-	markdown := convertTable(input, lang)
+func main() {
+	lang := getLanguage()
 
-	// Output result
-	shouldCopy := *toClipboard || *fromClipboard
-	outputResult(markdown, shouldCopy, *fromClipboard, lang)
+	args := os.Args[1:]
+	if len(args) > 0 {
+		if args[0] == "help" {
+			runHelp(args[1:], lang)
+			return
+		}
+		if cmd, ok := findCommand(args[0]); ok {
+			cmd.run(args[1:], lang)
+			return
+		}
+	}
+
+	// Without a subcommand, behave like convert (-clipboard, -copy, file arguments, ...)
+	runConvert(args, lang)
 }
 
-// getLinuxClipboardCommand returns the appropriate Linux clipboard command
-func getLinuxClipboardCommand(read bool) (*exec.Cmd, error) {
+// getLinuxClipboardCommand returns the appropriate Linux clipboard command.
+// On Wayland wl-clipboard is preferred; xclip and xsel are used on X11 or when wl-clipboard
+// is not installed (XWayland). wl-paste and xclip read the first of mimeTypes the clipboard offers
+func getLinuxClipboardCommand(read bool, mimeTypes []string) (*exec.Cmd, error) {
+	if isWayland() {
+		tool := "wl-copy"
+		if read {
+			tool = "wl-paste"
+		}
+		if _, err := exec.LookPath(tool); err == nil {
+			return getWaylandClipboardCommand(read, mimeTypes)
+		}
+	}
 	if _, err := exec.LookPath("xclip"); err == nil {
 		if read {
-			return exec.Command("xclip", "-selection", "clipboard", "-out"), nil
+			return getXclipReadCommand(mimeTypes), nil
 		}
 		return exec.Command("xclip", "-selection", "clipboard"), nil
 	}
@@ -236,12 +344,31 @@ func getLinuxClipboardCommand(read bool) (*exec.Cmd, error) {
 		return exec.Command("xsel", "--clipboard", "--input"), nil
 	}
 	lang := getLanguage()
+	if isWayland() {
+		if lang == "zh" {
+			return nil, fmt.Errorf("需要安装 wl-clipboard: sudo apt-get install wl-clipboard")
+		}
+		return nil, fmt.Errorf("wl-clipboard required: sudo apt-get install wl-clipboard")
+	}
 	if lang == "zh" {
 		return nil, fmt.Errorf("需要安装 xclip 或 xsel: sudo apt-get install xclip 或 sudo apt-get install xsel")
 	}
 	return nil, fmt.Errorf("xclip or xsel required: sudo apt-get install xclip or sudo apt-get install xsel")
 }
 
+// getXclipReadCommand returns the xclip read command, requesting the first of mimeTypes
+// listed in the clipboard's TARGETS; when the query fails or nothing matches, xclip
+// picks its default text target
+func getXclipReadCommand(mimeTypes []string) *exec.Cmd {
+	output, err := exec.Command("xclip", "-selection", "clipboard", "-target", "TARGETS", "-out").Output()
+	if err == nil {
+		if target := chooseClipboardType(parseClipboardTypes(string(output)), mimeTypes); target != "" {
+			return exec.Command("xclip", "-selection", "clipboard", "-target", target, "-out")
+		}
+	}
+	return exec.Command("xclip", "-selection", "clipboard", "-out")
+}
+
 // getUnsupportedOSError returns error message for unsupported OS
 func getUnsupportedOSError() error {
 	lang := getLanguage()
@@ -252,7 +379,8 @@ func getUnsupportedOSError() error {
 }
 
 // readFromClipboard 从剪贴板读取数据（跨平台实现）
-func readFromClipboard() (string, error) {
+// mimeTypes 为按优先级排列的剪贴板类型，只有 Linux 上的 wl-paste 和 xclip 支持选择类型
+func readFromClipboard(mimeTypes []string) (string, error) {
 	var cmd *exec.Cmd
 	var err error
 
@@ -260,7 +388,7 @@ func readFromClipboard() (string, error) {
 	case "darwin":
 		cmd = exec.Command("pbpaste")
 	case "linux":
-		cmd, err = getLinuxClipboardCommand(true)
+		cmd, err = getLinuxClipboardCommand(true, mimeTypes)
 		if err != nil {
 			return "", err
 		}
@@ -287,7 +415,7 @@ func writeToClipboard(data string) error {
 		cmd = exec.Command("pbcopy")
 		cmd.Stdin = strings.NewReader(data)
 	case "linux":
-		cmd, err = getLinuxClipboardCommand(false)
+		cmd, err = getLinuxClipboardCommand(false, nil)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// separatorCellRegex matches a GFM delimiter row cell: ---, :--, --:, :-:
+	separatorCellRegex = regexp.MustCompile(`^:?-+:?$`)
+	// fenceRegex matches the opening or closing line of a fenced code block
+	fenceRegex = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+)
+
+// MarkdownTable Markdown 文档中的一个 GFM 管道表格
+type MarkdownTable struct {
+	StartLine  int        // 表头所在行（从 0 开始）
+	EndLine    int        // 表格之后的第一行（从 0 开始，不包含）
+	Rows       [][]string // 表头和数据行（不含分隔行），单元格已去掉首尾空白
+	Alignments []string   // 每列的对齐方式 "l"、"c"、"r"
+}
+
+// FindMarkdownTables 查找文档中的所有 GFM 管道表格，跳过围栏代码块和缩进代码块
+func FindMarkdownTables(lines []string) []MarkdownTable {
+	var tables []MarkdownTable
+	inCode := codeBlockLines(lines)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if inCode[i] || i+1 >= len(lines) || inCode[i+1] || !strings.Contains(line, "|") {
+			continue
+		}
+		header := SplitTableRow(line)
+		alignments, ok := parseSeparatorRow(lines[i+1])
+		if !ok || len(alignments) != len(header) {
+			continue
+		}
+
+		table := MarkdownTable{StartLine: i, Rows: [][]string{header}, Alignments: alignments}
+		end := i + 2
+		for end < len(lines) && !inCode[end] && IsTableRow(lines[end]) {
+			table.Rows = append(table.Rows, SplitTableRow(lines[end]))
+			end++
+		}
+		table.EndLine = end
+		tables = append(tables, table)
+		i = end - 1
+	}
+	return tables
+}
+
+// IsTableRow 判断一行是否可能是表格行（非空且包含管道符）
+func IsTableRow(line string) bool {
+	return strings.TrimSpace(line) != "" && strings.Contains(line, "|")
+}
+
+// SplitTableRow 将表格行拆分为单元格，去掉首尾的管道符和单元格的首尾空白
+// 转义的管道符 \| 保留在单元格中
+func SplitTableRow(line string) []string {
+	cells := splitTableCells(line)
+	texts := make([]string, len(cells))
+	for i, cell := range cells {
+		texts[i] = cell.Text
+	}
+	return texts
+}
+
+// tableCell 表格行中的一个单元格及其位置
+type tableCell struct {
+	Text   string // 去掉首尾空白后的内容
+	Column int    // 内容在行中的起始列（从 1 开始，按字节计算）
+}
+
+// splitTableCells 将表格行拆分为单元格，并记录每个单元格在行中的位置
+func splitTableCells(line string) []tableCell {
+	start := len(line) - len(strings.TrimLeftFunc(line, unicode.IsSpace))
+	end := len(strings.TrimRightFunc(line, unicode.IsSpace))
+	if start < end && line[start] == '|' {
+		start++
+	}
+	if start < end && line[end-1] == '|' && (end-start < 2 || line[end-2] != '\\') {
+		end--
+	}
+
+	var cells []tableCell
+	cellStart := start
+	for i := start; i < end; i++ {
+		switch line[i] {
+		case '\\':
+			i++ // 跳过被转义的字符
+		case '|':
+			cells = append(cells, newTableCell(line, cellStart, i))
+			cellStart = i + 1
+		}
+	}
+	return append(cells, newTableCell(line, cellStart, max(cellStart, end)))
+}
+
+// newTableCell 根据 line[start:end] 创建单元格，去掉首尾空白
+func newTableCell(line string, start, end int) tableCell {
+	raw := line[start:end]
+	text := strings.TrimSpace(raw)
+	if text == "" {
+		return tableCell{Column: start + 1}
+	}
+	leading := len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace))
+	return tableCell{Text: text, Column: start + leading + 1}
+}
+
+// parseSeparatorRow 解析分隔行，返回每列的对齐方式
+func parseSeparatorRow(line string) ([]string, bool) {
+	if !strings.Contains(line, "-") {
+		return nil, false
+	}
+	cells := SplitTableRow(line)
+	alignments := make([]string, len(cells))
+	for i, cell := range cells {
+		if !separatorCellRegex.MatchString(cell) {
+			return nil, false
+		}
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			alignments[i] = "c"
+		case right:
+			alignments[i] = "r"
+		default:
+			alignments[i] = "l"
+		}
+	}
+	return alignments, true
+}
+
+// codeBlockLines 标记每一行是否属于代码块
+// 包括围栏代码块（含开始和结束标记行），以及空行或代码块之后缩进至少 4 列的缩进代码块；
+// 段落之后的缩进行是段落的延续，不算代码块
+func codeBlockLines(lines []string) []bool {
+	inCode := make([]bool, len(lines))
+	fence := ""
+	afterBlank := true // 文档开头与空行之后相同，可以开始缩进代码块
+	indented := false  // 当前是否在缩进代码块中（其中的空行不结束代码块）
+	for i, line := range lines {
+		if fence != "" {
+			inCode[i] = true
+			if isClosingFence(line, fence) {
+				fence = ""
+				afterBlank = true
+			}
+			continue
+		}
+		if matches := fenceRegex.FindStringSubmatch(line); matches != nil {
+			inCode[i] = true
+			fence = matches[1]
+			indented = false
+			continue
+		}
+
+		blank := strings.TrimSpace(line) == ""
+		switch {
+		case !blank && isIndentedCode(line) && (afterBlank || indented):
+			inCode[i] = true
+			indented = true
+		case !blank:
+			indented = false
+		}
+		afterBlank = blank
+	}
+	return inCode
+}
+
+// isIndentedCode 判断行首空白是否至少缩进 4 列（制表符按 4 列的制表位计算）
+func isIndentedCode(line string) bool {
+	column := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			column++
+		case '\t':
+			column += 4 - column%4
+		default:
+			return column >= 4
+		}
+		if column >= 4 {
+			return true
+		}
+	}
+	return false
+}
+
+// isClosingFence 判断是否为与开始标记匹配的结束围栏
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false
+	}
+	run := len(trimmed) - len(strings.TrimLeft(trimmed, fence[:1]))
+	return run >= len(fence) && strings.TrimSpace(trimmed[run:]) == ""
+}
+
+// splitLinesKeepEnds 按 \n 拆分文档，返回保留各自换行符的原始行，以及去掉 \n 或 \r\n 后的行内容
+// 原始行直接拼接即可还原文档，便于只替换部分行而不改动其他行的换行符
+func splitLinesKeepEnds(doc string) ([]string, []string) {
+	raw := strings.SplitAfter(doc, "\n")
+	lines := make([]string, len(raw))
+	for i, line := range raw {
+		lines[i] = strings.TrimSuffix(line, lineEnding(line))
+	}
+	return raw, lines
+}
+
+// lineEnding 返回原始行末尾的换行符：\r\n、\n，最后一行没有换行符时返回空字符串
+func lineEnding(line string) string {
+	switch {
+	case strings.HasSuffix(line, "\r\n"):
+		return "\r\n"
+	case strings.HasSuffix(line, "\n"):
+		return "\n"
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitTableRow(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"标准行", "| a | b |", []string{"a", "b"}},
+		{"无首尾管道符", "a | b", []string{"a", "b"}},
+		{"紧凑行", "|a|b|", []string{"a", "b"}},
+		{"转义管道符", `| a \| b | c |`, []string{`a \| b`, "c"}},
+		{"空单元格", "| | b |", []string{"", "b"}},
+		{"末尾转义管道符", `| a | b \|`, []string{"a", `b \|`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SplitTableRow(tt.input)
+			if !equalRows([][]string{result}, [][]string{tt.expected}) {
+				t.Errorf("SplitTableRow(%q) = %q, 期望 %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitTableCellsColumns(t *testing.T) {
+	cells := splitTableCells("  | a |  | 中文 \\| x |")
+	expected := []tableCell{{"a", 5}, {"", 8}, {"中文 \\| x", 12}}
+	if len(cells) != len(expected) {
+		t.Fatalf("splitTableCells() = %v, 期望 %v", cells, expected)
+	}
+	for i := range cells {
+		if cells[i] != expected[i] {
+			t.Errorf("splitTableCells()[%d] = %v, 期望 %v", i, cells[i], expected[i])
+		}
+	}
+}
+
+func TestFindMarkdownTables(t *testing.T) {
+	doc := strings.Join([]string{
+		"# Title",
+		"",
+		"| Name | ^Price |",
+		"|:-----|------:|",
+		"| Jane | 10 |",
+		"| John | 20 |",
+		"",
+		"```",
+		"| a | b |",
+		"|---|---|",
+		"```",
+		"",
+		"a | b | c",
+		"--|:-:|--",
+		"1 | 2 | 3",
+		"text after",
+		"",
+		"| not | a table |",
+		"| --- |",
+		"",
+		"    | code | block |",
+		"    |------|-------|",
+		"",
+		"\t| tab | code |",
+		"\t|-----|------|",
+	}, "\n")
+
+	tables := FindMarkdownTables(strings.Split(doc, "\n"))
+	if len(tables) != 2 {
+		t.Fatalf("FindMarkdownTables() 找到 %d 个表格, 期望 2", len(tables))
+	}
+
+	first := tables[0]
+	if first.StartLine != 2 || first.EndLine != 6 {
+		t.Errorf("第一个表格范围 = [%d, %d), 期望 [2, 6)", first.StartLine, first.EndLine)
+	}
+	if !equalRows(first.Rows, [][]string{{"Name", "^Price"}, {"Jane", "10"}, {"John", "20"}}) {
+		t.Errorf("第一个表格内容 = %v", first.Rows)
+	}
+	if strings.Join(first.Alignments, ",") != "l,r" {
+		t.Errorf("第一个表格对齐 = %v, 期望 [l r]", first.Alignments)
+	}
+
+	second := tables[1]
+	if second.StartLine != 12 || second.EndLine != 15 {
+		t.Errorf("第二个表格范围 = [%d, %d), 期望 [12, 15)", second.StartLine, second.EndLine)
+	}
+	if strings.Join(second.Alignments, ",") != "l,c,l" {
+		t.Errorf("第二个表格对齐 = %v, 期望 [l c l]", second.Alignments)
+	}
+}
+
+func TestConvertToMarkdownStyles(t *testing.T) {
+	input := func() [][]string {
+		return [][]string{{"name", "^rqty", "^cok"}, {"apple", "3", "y"}}
+	}
+
+	compact := NewConverter()
+	compact.Style = "compact"
+	expected := "|name|qty|ok|\n|---|--:|:-:|\n|apple|3|y|"
+	if result := compact.ConvertToMarkdown(input()); result != expected {
+		t.Errorf("compact 样式 = %q, 期望 %q", result, expected)
+	}
+
+	stable := NewConverter()
+	stable.Style = "stable"
+	stable.Previous = [][]string{{"qty", "name"}, {"12345", "pear"}}
+	expected = "| name   | qty    | ok  |\n|--------|-------:|:---:|\n| apple  | 3      | y   |"
+	if result := stable.ConvertToMarkdown(input()); result != expected {
+		t.Errorf("stable 样式 = %q, 期望 %q", result, expected)
+	}
+}
+
+func TestCodeBlockLines(t *testing.T) {
+	lines := []string{
+		"    code at start", // 0: 文档开头的缩进代码块
+		"",                  // 1
+		"    still code",    // 2: 空行之后的缩进代码块
+		"text",              // 3
+		"    continuation",  // 4: 段落的延续
+		"",                  // 5
+		"\tcode",            // 6: 制表符缩进
+		"  ```",             // 7: 围栏代码块
+		"    fenced",        // 8
+		"  ```",             // 9
+		"    after fence",   // 10: 围栏之后的缩进代码块
+	}
+	expected := []bool{true, false, true, false, false, false, true, true, true, true, true}
+
+	result := codeBlockLines(lines)
+	for i := range lines {
+		if result[i] != expected[i] {
+			t.Errorf("codeBlockLines() 第 %d 行 = %v, 期望 %v", i, result[i], expected[i])
+		}
+	}
+}
+
+func TestSplitLinesKeepEnds(t *testing.T) {
+	doc := "a\r\nb\nc\rd\n\nlast"
+	raw, lines := splitLinesKeepEnds(doc)
+	expectedRaw := []string{"a\r\n", "b\n", "c\rd\n", "\n", "last"}
+	expectedLines := []string{"a", "b", "c\rd", "", "last"}
+	if strings.Join(raw, "|") != strings.Join(expectedRaw, "|") {
+		t.Errorf("splitLinesKeepEnds() 原始行 = %q, 期望 %q", raw, expectedRaw)
+	}
+	if strings.Join(lines, "|") != strings.Join(expectedLines, "|") {
+		t.Errorf("splitLinesKeepEnds() 行内容 = %q, 期望 %q", lines, expectedLines)
+	}
+	if strings.Join(raw, "") != doc {
+		t.Errorf("拼接原始行 = %q, 期望还原文档 %q", strings.Join(raw, ""), doc)
+	}
+}
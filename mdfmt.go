@@ -0,0 +1,44 @@
+package main
+
+import "strings"
+
+// FormatMarkdownTables 重新格式化文档中的所有 GFM 管道表格，其余内容保持不变
+// 表格按 ConvertToMarkdown 的填充方式重新生成，保留原有的对齐方式、缩进和每行的换行符；
+// 行的单元格多于表头的表格不做处理，避免丢失内容
+func FormatMarkdownTables(doc string) string {
+	raw, lines := splitLinesKeepEnds(doc)
+	for _, table := range FindMarkdownTables(lines) {
+		formatted, ok := formatTable(table)
+		if !ok {
+			continue
+		}
+		indent := leadingIndent(lines[table.StartLine])
+		// 表格的行数不变：表头、分隔行和每个数据行各占一行
+		for i, line := range strings.Split(formatted, "\n") {
+			index := table.StartLine + i
+			raw[index] = indent + line + lineEnding(raw[index])
+		}
+	}
+	return strings.Join(raw, "")
+}
+
+// formatTable 重新生成单个表格，较短的行用空单元格补齐
+func formatTable(table MarkdownTable) (string, bool) {
+	width := len(table.Rows[0])
+	rows := make([][]string, len(table.Rows))
+	for i, row := range table.Rows {
+		if len(row) > width {
+			return "", false
+		}
+		rows[i] = append(row[:len(row):len(row)], make([]string, width-len(row))...)
+	}
+
+	converter := NewConverter()
+	converter.Alignments = table.Alignments
+	return converter.ConvertToMarkdown(rows), true
+}
+
+// leadingIndent 返回行首的空白字符
+func leadingIndent(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
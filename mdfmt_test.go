@@ -0,0 +1,69 @@
+package main
+
+import "testing"
+
+func TestFormatMarkdownTables(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		expected string
+	}{
+		{
+			"格式化表格并保留其他内容",
+			"# Title  \n\n|Name|^rate|\n|:-|--:|\n|Jane|1|\n\ntext |  kept\n",
+			"# Title  \n\n| Name  | ^rate  |\n|-------|-------:|\n| Jane  | 1      |\n\ntext |  kept\n",
+		},
+		{
+			"保留居中对齐和转义管道符",
+			"a | b\n:-:|---\n`x \\| y` | 中文",
+			"| a         | b     |\n|:---------:|-------|\n| `x \\| y`  | 中文  |",
+		},
+		{
+			"补齐较短的行",
+			"| a | b |\n|---|---|\n| 1 |",
+			"| a  | b  |\n|----|----|\n| 1  |    |",
+		},
+		{
+			"单元格多于表头时不处理",
+			"| a |\n|---|\n| 1 | 2 |",
+			"| a |\n|---|\n| 1 | 2 |",
+		},
+		{
+			"跳过代码块",
+			"```\n|a|b|\n|-|-|\n```",
+			"```\n|a|b|\n|-|-|\n```",
+		},
+		{
+			"跳过缩进代码块",
+			"Intro\n\n    | a | b |\n    |---|---|\n\n    | 1 | 2 |\n\n|x|\n|-|",
+			"Intro\n\n    | a | b |\n    |---|---|\n\n    | 1 | 2 |\n\n| x  |\n|----|",
+		},
+		{
+			"保留缩进",
+			"- item\n\n  |a|\n  |-|\n  |1|",
+			"- item\n\n  | a  |\n  |----|\n  | 1  |",
+		},
+		{
+			"保留 CRLF 换行",
+			"|a|\r\n|-|\r\n|1|\r\n",
+			"| a  |\r\n|----|\r\n| 1  |\r\n",
+		},
+		{
+			"保留每行原有的换行符",
+			"Intro\r\nOther\n|a|\r\n|-|\n|1|\r\nc\rd\u2028e\n",
+			"Intro\r\nOther\n| a  |\r\n|----|\n| 1  |\r\nc\rd\u2028e\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatMarkdownTables(tt.doc)
+			if result != tt.expected {
+				t.Errorf("FormatMarkdownTables() =\n%s\n期望\n%s", result, tt.expected)
+			}
+			if again := FormatMarkdownTables(result); again != result {
+				t.Errorf("再次格式化结果不同:\n%s", again)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RowIssue 单元格数量与表头不一致的行
+type RowIssue struct {
+	Line     int // 输入中的行号（从 1 开始），未知时为 0
+	Cells    int // 实际单元格数
+	Expected int // 表头的单元格数
+}
+
+// String 返回问题描述
+func (i RowIssue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("row has %d cells, expected %d", i.Cells, i.Expected)
+	}
+	return fmt.Sprintf("line %d has %d cells, expected %d", i.Line, i.Cells, i.Expected)
+}
+
+// NormalizeRows 将所有行规范为与表头相同的列数
+// 较短的行用空单元格补齐；较长的行按 overflow 处理：
+//   - "extend": 用生成的列名扩展表头（默认）
+//   - "merge": 将多余的单元格合并到最后一列
+//   - "truncate": 丢弃多余的单元格
+//
+// lines 为每行在输入中的行号，用于报告问题，可以为 nil
+func NormalizeRows(rows [][]string, lines []int, overflow string) ([][]string, []RowIssue, error) {
+	if len(rows) == 0 {
+		return rows, nil, nil
+	}
+
+	switch overflow {
+	case "", "extend", "merge", "truncate":
+	default:
+		return nil, nil, fmt.Errorf("invalid overflow mode %q, expected extend, merge or truncate", overflow)
+	}
+
+	expected := len(rows[0])
+	var issues []RowIssue
+	width := expected
+	for i, row := range rows[1:] {
+		if len(row) == expected {
+			continue
+		}
+		line := 0
+		if i+1 < len(lines) {
+			line = lines[i+1]
+		}
+		issues = append(issues, RowIssue{Line: line, Cells: len(row), Expected: expected})
+		width = max(width, len(row))
+	}
+
+	if overflow == "" || overflow == "extend" {
+		// 用生成的列名扩展表头
+		generated := SyntheticHeader(width, "column")
+		for len(rows[0]) < width {
+			rows[0] = append(rows[0], generated[len(rows[0])])
+		}
+		expected = width
+	}
+
+	for i, row := range rows {
+		switch {
+		case len(row) < expected:
+			rows[i] = append(row, make([]string, expected-len(row))...)
+		case len(row) > expected && overflow == "merge":
+			merged := strings.Join(row[expected-1:], " ")
+			rows[i] = append(row[:expected-1], merged)
+		case len(row) > expected:
+			rows[i] = row[:expected]
+		}
+	}
+	return rows, issues, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestNormalizeRows(t *testing.T) {
+	tests := []struct {
+		name           string
+		overflow       string
+		expected       [][]string
+		expectedIssues []RowIssue
+	}{
+		{
+			"扩展表头",
+			"extend",
+			[][]string{{"a", "b", "Column 3"}, {"1", "2", "3"}, {"4", "", ""}, {"5", "6", ""}},
+			[]RowIssue{{Line: 2, Cells: 3, Expected: 2}, {Line: 4, Cells: 1, Expected: 2}},
+		},
+		{
+			"合并到最后一列",
+			"merge",
+			[][]string{{"a", "b"}, {"1", "2 3"}, {"4", ""}, {"5", "6"}},
+			[]RowIssue{{Line: 2, Cells: 3, Expected: 2}, {Line: 4, Cells: 1, Expected: 2}},
+		},
+		{
+			"截断",
+			"truncate",
+			[][]string{{"a", "b"}, {"1", "2"}, {"4", ""}, {"5", "6"}},
+			[]RowIssue{{Line: 2, Cells: 3, Expected: 2}, {Line: 4, Cells: 1, Expected: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := [][]string{{"a", "b"}, {"1", "2", "3"}, {"4"}, {"5", "6"}}
+			lines := []int{1, 2, 4, 5}
+			result, issues, err := NormalizeRows(rows, lines, tt.overflow)
+			if err != nil {
+				t.Fatalf("NormalizeRows() error = %v", err)
+			}
+			if !equalRows(result, tt.expected) {
+				t.Errorf("NormalizeRows() = %v, 期望 %v", result, tt.expected)
+			}
+			if len(issues) != len(tt.expectedIssues) {
+				t.Fatalf("NormalizeRows() 问题数 = %d, 期望 %d", len(issues), len(tt.expectedIssues))
+			}
+			for i := range issues {
+				if issues[i] != tt.expectedIssues[i] {
+					t.Errorf("问题[%d] = %+v, 期望 %+v", i, issues[i], tt.expectedIssues[i])
+				}
+			}
+		})
+	}
+
+	if _, _, err := NormalizeRows([][]string{{"a"}}, nil, "drop"); err == nil {
+		t.Error("无效的 overflow 模式应返回错误")
+	}
+}
+
+func TestRowIssueString(t *testing.T) {
+	if s := (RowIssue{Line: 3, Cells: 1, Expected: 2}).String(); s != "line 3 has 1 cells, expected 2" {
+		t.Errorf("RowIssue.String() = %q", s)
+	}
+	if s := (RowIssue{Cells: 1, Expected: 2}).String(); s != "row has 1 cells, expected 2" {
+		t.Errorf("RowIssue.String() = %q", s)
+	}
+}
+
+func TestNormalizeRowsUnknownLine(t *testing.T) {
+	rows := [][]string{{"a", "b"}, {"1", "2", "3"}}
+	_, err := normalizeRows(rows, nil, options{overflow: "extend", strict: true}, "en")
+	if err == nil || err.Error() != "row has 3 cells, expected 2" {
+		t.Errorf("normalizeRows() error = %v, 期望不包含行号", err)
+	}
+}
+
+func TestLineNumbers(t *testing.T) {
+	converter := NewConverter()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{"TSV跳过空行", "\n\na\tb\n\n1\t2\n3\t4", []int{3, 5, 6}},
+		{"CSV多行字段", "a,b\n\"x\ny\",2\n\n3,4", []int{1, 2, 5}},
+		{"Column格式", "Name    Age\n\nJohn    25", []int{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := converter.ParseTable(tt.input); err != nil {
+				t.Fatalf("ParseTable() error = %v", err)
+			}
+			lines := converter.LineNumbers()
+			if len(lines) != len(tt.expected) {
+				t.Fatalf("LineNumbers() = %v, 期望 %v", lines, tt.expected)
+			}
+			for i := range lines {
+				if lines[i] != tt.expected[i] {
+					t.Errorf("LineNumbers() = %v, 期望 %v", lines, tt.expected)
+					break
+				}
+			}
+		})
+	}
+}
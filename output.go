@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteFileAtomic 原子地写入文件：先写入同一目录中的临时文件，成功后再重命名为目标文件
+// 写入失败时目标文件保持不变；目标文件已存在时沿用其权限
+func WriteFileAtomic(path string, data []byte) (err error) {
+	mode := fs.FileMode(0o644)
+	if info, statErr := os.Stat(path); statErr == nil {
+		mode = info.Mode().Perm()
+	} else if !errors.Is(statErr, fs.ErrNotExist) {
+		return statErr
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(mode); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// AppendDocument 将内容追加到已有文档末尾，两者之间保留一个空行
+func AppendDocument(existing, addition string) string {
+	existing = strings.TrimRight(existing, "\r\n")
+	if existing == "" {
+		return addition
+	}
+	return existing + "\n\n" + addition
+}
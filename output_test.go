@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "table.md")
+
+	if err := WriteFileAtomic(path, []byte("first\n")); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte("second\n")); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "second\n" {
+		t.Errorf("文件内容 = %q, 期望 %q", data, "second\n")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("文件权限 = %v, 期望 0600", info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("目录中有 %d 个文件, 期望临时文件已删除", len(entries))
+	}
+
+	if err := WriteFileAtomic(filepath.Join(dir, "missing", "table.md"), []byte("x")); err == nil {
+		t.Error("WriteFileAtomic() 期望目录不存在时报错")
+	}
+}
+
+func TestAppendDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		addition string
+		expected string
+	}{
+		{"空文档", "", "| a |", "| a |"},
+		{"追加到末尾", "# Title\n", "| a |", "# Title\n\n| a |"},
+		{"去掉多余空行", "# Title\r\n\r\n\r\n", "| a |", "# Title\n\n| a |"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := AppendDocument(tt.existing, tt.addition)
+			if result != tt.expected {
+				t.Errorf("AppendDocument() = %q, 期望 %q", result, tt.expected)
+			}
+		})
+	}
+}
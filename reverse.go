@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// ReverseTable 将 Markdown 表格的单元格转换回 TSV 或 CSV 文本
+// 单元格中转义的管道符 \| 还原为 |，<br> 换行还原为换行符（TSV 中替换为空格）
+func ReverseTable(rows [][]string, format string) (string, error) {
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = make([]string, len(row))
+		for j, cell := range row {
+			cells[i][j] = unescapeMarkdownCell(cell)
+		}
+	}
+
+	switch format {
+	case "", "tsv":
+		var b strings.Builder
+		for _, row := range cells {
+			for j, cell := range row {
+				// TSV 没有转义规则，制表符和换行符会破坏行列结构
+				row[j] = strings.NewReplacer("\t", " ", "\n", " ").Replace(cell)
+			}
+			b.WriteString(strings.Join(row, "\t"))
+			b.WriteByte('\n')
+		}
+		return b.String(), nil
+	case "csv":
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		if err := writer.WriteAll(cells); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	return "", fmt.Errorf("invalid output format %q, expected tsv or csv", format)
+}
+
+// unescapeMarkdownCell 还原单元格中的 Markdown 转义
+func unescapeMarkdownCell(cell string) string {
+	cell = strings.ReplaceAll(cell, `\|`, "|")
+	return strings.ReplaceAll(cell, wrapSeparator, "\n")
+}
@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestReverseTable(t *testing.T) {
+	rows := [][]string{
+		{"Name", "Note"},
+		{"Jane", `a \| b`},
+		{"John, Jr.", "line 1<br>line 2"},
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+		wantErr  bool
+	}{
+		{"TSV", "tsv", "Name\tNote\nJane\ta | b\nJohn, Jr.\tline 1 line 2\n", false},
+		{"默认 TSV", "", "Name\tNote\nJane\ta | b\nJohn, Jr.\tline 1 line 2\n", false},
+		{"CSV", "csv", "Name,Note\nJane,a | b\n\"John, Jr.\",\"line 1\nline 2\"\n", false},
+		{"无效格式", "xlsx", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ReverseTable(rows, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReverseTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("ReverseTable() = %q, 期望 %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// invisibleReplacer 删除 BOM 和零宽字符，将各种不换行空格替换为普通空格
+// 零宽连接符（U+200D）和零宽非连接符（U+200C）会影响 emoji 序列和部分文字的显示，予以保留
+var invisibleReplacer = strings.NewReplacer(
+	"\uFEFF", "", // BOM / 零宽不换行空格
+	"\u200B", "", // 零宽空格
+	"\u2060", "", // 词连接符
+	"\u00AD", "", // 软连字符
+	"\u00A0", " ", // 不换行空格
+	"\u202F", " ", // 窄不换行空格
+	"\u2007", " ", // 数字空格
+)
+
+// SanitizeCell 清理单元格中的不可见字符
+// 删除 BOM 和零宽字符，将不换行空格替换为普通空格，并总是去掉首尾空白；
+// nfc 为 true 时同时进行 Unicode NFC 规范化（例如将 "e" + 组合重音符合并为 "é"）
+func SanitizeCell(cell string, nfc bool) string {
+	cleaned := strings.TrimSpace(invisibleReplacer.Replace(cell))
+	if nfc {
+		cleaned = norm.NFC.String(cleaned)
+	}
+	return cleaned
+}
+
+// SanitizeRows 清理所有单元格中的不可见字符，直接修改 rows
+func SanitizeRows(rows [][]string, nfc bool) [][]string {
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = SanitizeCell(cell, nfc)
+		}
+	}
+	return rows
+}
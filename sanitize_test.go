@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestSanitizeCell(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		nfc      bool
+		expected string
+	}{
+		{"普通文本不变", "Name", false, "Name"},
+		{"去掉普通首尾空格", " a ", false, "a"},
+		{"与零宽空格无关", "\u200B a ", false, "a"},
+		{"BOM", "\uFEFFName", false, "Name"},
+		{"不换行空格", "New\u00A0York", false, "New York"},
+		{"首尾不换行空格", "\u00A0价格\u00A0", false, "价格"},
+		{"窄不换行空格", "1\u202F000", false, "1 000"},
+		{"零宽空格", "foo\u200Bbar", false, "foobar"},
+		{"词连接符和软连字符", "co\u00ADop\u2060er", false, "cooper"},
+		{"保留零宽连接符", "👨\u200D👩", false, "👨\u200D👩"},
+		{"不启用 NFC", "e\u0301", false, "e\u0301"},
+		{"启用 NFC", "Cafe\u0301", true, "Café"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SanitizeCell(tt.input, tt.nfc)
+			if result != tt.expected {
+				t.Errorf("SanitizeCell(%q) = %q, 期望 %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTableSanitizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected [][]string
+	}{
+		{"CSV BOM 和带引号的表头", "\uFEFF\"Name\",Age\nJane,30", [][]string{{"Name", "Age"}, {"Jane", "30"}}},
+		{"TSV 网页表格", "Name\u00A0\tCity\nJane\u200B\tNew\u00A0York", [][]string{{"Name", "City"}, {"Jane", "New York"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := NewConverter().ParseTable(tt.input)
+			if err != nil {
+				t.Fatalf("ParseTable() error = %v", err)
+			}
+			if !equalRows(rows, tt.expected) {
+				t.Errorf("ParseTable() = %q, 期望 %q", rows, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxRequestSize limits the size of a table posted to the server
+const maxRequestSize = 10 << 20
+
+// serverBlockedFlags are conversion flags that read or write local files or the
+// clipboard, so they cannot be used with serve
+var serverBlockedFlags = map[string]bool{
+	"clipboard": true,
+	"copy":      true,
+	"html":      true,
+	"previous":  true,
+	"outdir":    true,
+	"o":         true,
+	"append":    true,
+	"update":    true,
+	"check":     true,
+}
+
+// runServe implements the serve subcommand: an HTTP server that converts table data
+// posted to /convert. Conversion flags given on the command line are the defaults,
+// and query parameters with the same names override them per request
+func runServe(args []string, lang string) {
+	flags, opts := newConvertFlags("serve", lang)
+	addr := flags.String("addr", "localhost:8080", errorMsg(lang, "监听地址", "Address to listen on"))
+	setupUsage(flags, lang)
+	flags.Parse(args)
+
+	flags.Visit(func(f *flag.Flag) {
+		if serverBlockedFlags[f.Name] {
+			printErrorf(lang, "错误: serve 不支持 -%s", "Error: -%s cannot be used with serve", f.Name)
+		}
+	})
+	exitOnError(lang, applyConfig(flags, opts.profile, serverBlockedFlags, lang))
+	exitOnError(lang, validateOptions(*opts, lang))
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           newServeMux(*opts, lang),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(os.Stderr, errorMsg(lang, "正在监听 http://%s ，将表格数据 POST 到 /convert", "Listening on http://%s, POST table data to /convert")+"\n", *addr)
+	exitOnError(lang, server.ListenAndServe())
+}
+
+// newServeMux returns the HTTP handlers of the server
+func newServeMux(defaults options, lang string) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /convert", func(w http.ResponseWriter, r *http.Request) {
+		opts, err := requestOptions(defaults, r.URL.Query(), lang)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		input, err := DecodeInput(data, opts.encoding)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		input = strings.TrimRight(input, "\r\n")
+		if strings.TrimSpace(input) == "" {
+			http.Error(w, errorMsg(lang, "没有输入数据", "No input data"), http.StatusBadRequest)
+			return
+		}
+
+		markdown, err := convertTable(input, opts, lang)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		fmt.Fprintln(w, markdown)
+	})
+	return mux
+}
+
+// requestOptions applies the query parameters of a request on top of the server defaults.
+// Parameter names are the conversion flag names, e.g. ?sort=price:desc&style=compact
+func requestOptions(defaults options, query url.Values, lang string) (options, error) {
+	flags, opts := newConvertFlags("request", lang)
+	*opts = defaults
+	for name, values := range query {
+		if serverBlockedFlags[name] || name == "profile" || flags.Lookup(name) == nil {
+			return options{}, errorf(lang, "不支持的参数: %s", "Unsupported parameter: %s", name)
+		}
+		if err := flags.Set(name, values[len(values)-1]); err != nil {
+			return options{}, errorf(lang, "参数 %s 无效: %v", "Invalid parameter %s: %v", name, err)
+		}
+	}
+	return *opts, validateOptions(*opts, lang)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeConvert(t *testing.T) {
+	_, defaults := newConvertFlags("test", "en")
+	server := httptest.NewServer(newServeMux(*defaults, "en"))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		method   string
+		query    string
+		body     string
+		status   int
+		expected string
+	}{
+		{"转换 CSV", "POST", "", "Name,Age\nJane,30", http.StatusOK, "| Name  | Age  |\n|-------|------|\n| Jane  | 30   |\n"},
+		{"查询参数覆盖选项", "POST", "?sort=Age:desc&style=compact", "Name,Age\nJane,30\nJohn,45", http.StatusOK, "|Name|Age|\n|---|---|\n|John|45|\n|Jane|30|\n"},
+		{"不支持的参数", "POST", "?clipboard=true", "Name,Age\nJane,30", http.StatusBadRequest, "Unsupported parameter: clipboard\n"},
+		{"未知参数", "POST", "?nope=1", "Name,Age\nJane,30", http.StatusBadRequest, "Unsupported parameter: nope\n"},
+		{"无效的选项值", "POST", "?style=fancy", "Name,Age\nJane,30", http.StatusBadRequest, "Invalid output style: fancy\n"},
+		{"转换失败", "POST", "?where=missing%3D%3D1", "Name,Age\nJane,30", http.StatusBadRequest, "Invalid filter: unknown column \"missing\" (available: Name, Age)\n"},
+		{"没有输入", "POST", "", "\n", http.StatusBadRequest, "No input data\n"},
+		{"不允许 GET", "GET", "", "", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+"/convert"+tt.query, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Fatalf("状态码 = %d, 期望 %d", resp.StatusCode, tt.status)
+			}
+			if tt.expected == "" {
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.expected {
+				t.Errorf("响应 = %q, 期望 %q", body, tt.expected)
+			}
+		})
+	}
+}
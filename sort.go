@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// dateLayouts 排序时识别的日期格式
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05Z07:00",
+	"2006/1/2",
+	"2006/1/2 15:04:05",
+	"2006/1/2 15:04",
+	"1/2/2006",
+	"1/2/2006 15:04:05",
+	"1/2/2006 15:04",
+	"2006.1.2",
+	"2006年1月2日",
+	"Jan 2, 2006",
+	"2 Jan 2006",
+	"January 2, 2006",
+}
+
+// SortKey 排序键
+type SortKey struct {
+	Column     string // 列名或从 1 开始的列序号
+	Descending bool   // 是否降序
+	Type       string // 比较方式 "num"、"date"、"str"，空表示自动检测
+}
+
+// ParseSortKeys 解析排序规则，格式: "列名:asc,列名:desc:date"
+func ParseSortKeys(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		key := SortKey{Column: strings.TrimSpace(parts[0])}
+		for _, part := range parts[1:] {
+			switch strings.ToLower(strings.TrimSpace(part)) {
+			case "asc":
+				key.Descending = false
+			case "desc":
+				key.Descending = true
+			case "num", "date", "str":
+				key.Type = strings.ToLower(strings.TrimSpace(part))
+			default:
+				return nil, fmt.Errorf("invalid sort option %q in %q", part, item)
+			}
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no sort keys")
+	}
+	return keys, nil
+}
+
+// SortRows 按多个排序键对数据行进行稳定排序，表头行保持不变
+// 空单元格无论升序降序都排在最后
+func SortRows(rows [][]string, spec string) error {
+	if len(rows) < 2 {
+		return nil
+	}
+
+	keys, err := ParseSortKeys(spec)
+	if err != nil {
+		return err
+	}
+
+	comparers := make([]func(a, b []string) int, len(keys))
+	for i, key := range keys {
+		index, err := findColumn(rows[0], key.Column)
+		if err != nil {
+			return err
+		}
+		comparers[i] = newColumnComparer(rows[1:], index, key)
+	}
+
+	slices.SortStableFunc(rows[1:], func(a, b []string) int {
+		for _, compare := range comparers {
+			if cmp := compare(a, b); cmp != 0 {
+				return cmp
+			}
+		}
+		return 0
+	})
+	return nil
+}
+
+// newColumnComparer 根据列内容（或指定类型）创建列比较函数
+func newColumnComparer(rows [][]string, index int, key SortKey) func(a, b []string) int {
+	sortType := key.Type
+	if sortType == "" {
+		sortType = detectSortType(rows, index)
+	}
+
+	var compareValues func(a, b string) int
+	switch sortType {
+	case "num":
+		compareValues = compareNumbers
+	case "date":
+		compareValues = compareDates
+	default:
+		compareValues = newNaturalComparer()
+	}
+
+	return func(a, b []string) int {
+		left, right := cellAt(a, index), cellAt(b, index)
+		// 空值总是排在最后
+		switch {
+		case left == "" && right == "":
+			return 0
+		case left == "":
+			return 1
+		case right == "":
+			return -1
+		}
+		cmp := compareValues(left, right)
+		if key.Descending {
+			return -cmp
+		}
+		return cmp
+	}
+}
+
+// detectSortType 检测列的数据类型：全部为数字时为 num，全部为日期时为 date，否则为 str
+func detectSortType(rows [][]string, index int) string {
+	allNumbers, allDates, seen := true, true, false
+	for _, row := range rows {
+		cell := cellAt(row, index)
+		if cell == "" {
+			continue
+		}
+		seen = true
+		if _, ok := parseNumber(cell); !ok {
+			allNumbers = false
+		}
+		if _, ok := parseDate(cell); !ok {
+			allDates = false
+		}
+	}
+	switch {
+	case !seen:
+		return "str"
+	case allNumbers:
+		return "num"
+	case allDates:
+		return "date"
+	}
+	return "str"
+}
+
+// cellAt 返回去掉首尾空白的单元格内容，不存在时返回空字符串
+func cellAt(row []string, index int) string {
+	if index < len(row) {
+		return strings.TrimSpace(row[index])
+	}
+	return ""
+}
+
+// compareNumbers 按数值比较，非数字排在数字之后
+func compareNumbers(a, b string) int {
+	left, leftOK := parseNumber(a)
+	right, rightOK := parseNumber(b)
+	switch {
+	case leftOK && rightOK:
+		switch {
+		case left < right:
+			return -1
+		case left > right:
+			return 1
+		}
+		return 0
+	case leftOK:
+		return -1
+	case rightOK:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareDates 按日期比较，无法识别的日期排在之后
+func compareDates(a, b string) int {
+	left, leftOK := parseDate(a)
+	right, rightOK := parseDate(b)
+	switch {
+	case leftOK && rightOK:
+		return left.Compare(right)
+	case leftOK:
+		return -1
+	case rightOK:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// parseDate 尝试用常见格式解析日期
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// newNaturalComparer 创建自然排序比较函数：数字部分按数值比较（"item2" < "item10"），
+// 其余部分使用中文排序规则（按拼音，忽略大小写和全半角差异）
+func newNaturalComparer() func(a, b string) int {
+	collator := collate.New(language.Chinese, collate.Loose)
+	return func(a, b string) int {
+		leftChunks, rightChunks := splitNatural(a), splitNatural(b)
+		for i := 0; i < len(leftChunks) && i < len(rightChunks); i++ {
+			left, right := leftChunks[i], rightChunks[i]
+			leftDigit, rightDigit := isDigitChunk(left), isDigitChunk(right)
+			var cmp int
+			if leftDigit && rightDigit {
+				cmp = compareDigitChunks(left, right)
+			} else {
+				cmp = collator.CompareString(left, right)
+			}
+			if cmp != 0 {
+				return cmp
+			}
+		}
+		if cmp := len(leftChunks) - len(rightChunks); cmp != 0 {
+			return cmp
+		}
+		// 排序规则认为相等时（例如只有大小写不同）回退到字节比较，保证结果确定
+		return strings.Compare(a, b)
+	}
+}
+
+// splitNatural 将字符串拆分为连续数字和非数字的片段
+func splitNatural(s string) []string {
+	var chunks []string
+	start := 0
+	for i, r := range s {
+		if i > start && isASCIIDigit(r) != isASCIIDigit(rune(s[start])) {
+			chunks = append(chunks, s[start:i])
+			start = i
+		}
+	}
+	if start < len(s) {
+		chunks = append(chunks, s[start:])
+	}
+	return chunks
+}
+
+// compareDigitChunks 按数值比较两个数字片段，支持任意长度
+func compareDigitChunks(a, b string) int {
+	left, _ := new(big.Int).SetString(a, 10)
+	right, _ := new(big.Int).SetString(b, 10)
+	return left.Cmp(right)
+}
+
+func isDigitChunk(s string) bool {
+	return s != "" && isASCIIDigit(rune(s[0]))
+}
+
+func isASCIIDigit(r rune) bool {
+	return r < unicode.MaxASCII && unicode.IsDigit(r)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	keys, err := ParseSortKeys("priority:asc, due:desc:date,name")
+	if err != nil {
+		t.Fatalf("ParseSortKeys() error = %v", err)
+	}
+	expected := []SortKey{
+		{Column: "priority"},
+		{Column: "due", Descending: true, Type: "date"},
+		{Column: "name"},
+	}
+	if len(keys) != len(expected) {
+		t.Fatalf("ParseSortKeys() 返回 %d 个排序键, 期望 %d", len(keys), len(expected))
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Errorf("排序键[%d] = %+v, 期望 %+v", i, keys[i], expected[i])
+		}
+	}
+
+	if _, err := ParseSortKeys("priority:up"); err == nil {
+		t.Error("无效排序方向应返回错误")
+	}
+	if _, err := ParseSortKeys(""); err == nil {
+		t.Error("空排序规则应返回错误")
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    [][]string
+		spec     string
+		expected [][]string
+	}{
+		{
+			"数值排序",
+			[][]string{{"n"}, {"10"}, {"9"}, {"1,000"}, {"-1"}},
+			"n",
+			[][]string{{"n"}, {"-1"}, {"9"}, {"10"}, {"1,000"}},
+		},
+		{
+			"日期降序",
+			[][]string{{"due"}, {"2024/1/5"}, {"2023/12/31"}, {"2024/1/15"}},
+			"due:desc",
+			[][]string{{"due"}, {"2024/1/15"}, {"2024/1/5"}, {"2023/12/31"}},
+		},
+		{
+			"自然排序",
+			[][]string{{"name"}, {"item10"}, {"item2"}, {"Item1"}},
+			"name",
+			[][]string{{"name"}, {"Item1"}, {"item2"}, {"item10"}},
+		},
+		{
+			"中文按拼音排序",
+			[][]string{{"姓名"}, {"赵六"}, {"张三"}, {"李四"}, {"王五"}},
+			"姓名",
+			[][]string{{"姓名"}, {"李四"}, {"王五"}, {"张三"}, {"赵六"}},
+		},
+		{
+			"多列排序且保持稳定",
+			[][]string{
+				{"team", "score", "name"},
+				{"b", "1", "first"},
+				{"a", "2", "second"},
+				{"b", "3", "third"},
+				{"a", "2", "fourth"},
+			},
+			"team:asc,score:desc",
+			[][]string{
+				{"team", "score", "name"},
+				{"a", "2", "second"},
+				{"a", "2", "fourth"},
+				{"b", "3", "third"},
+				{"b", "1", "first"},
+			},
+		},
+		{
+			"空值排在最后",
+			[][]string{{"n", "id"}, {"", "a"}, {"2", "b"}, {"1"}},
+			"n:desc",
+			[][]string{{"n", "id"}, {"2", "b"}, {"1"}, {"", "a"}},
+		},
+		{
+			"指定类型和列序号",
+			[][]string{{"^rcode"}, {"10"}, {"9"}},
+			"1:str",
+			[][]string{{"^rcode"}, {"9"}, {"10"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SortRows(tt.input, tt.spec); err != nil {
+				t.Fatalf("SortRows(%q) error = %v", tt.spec, err)
+			}
+			if !equalRows(tt.input, tt.expected) {
+				t.Errorf("SortRows(%q) = %v, 期望 %v", tt.spec, tt.input, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortRowsUnknownColumn(t *testing.T) {
+	rows := [][]string{{"a"}, {"1"}, {"2"}}
+	if err := SortRows(rows, "b"); err == nil {
+		t.Error("未知列名应返回错误")
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+// Transpose 行列转置，不规则的行会先用空单元格补齐为矩形
+// 转置后原表头成为第一列的数据，表头指令只作用于原来的列，因此会被去掉，只保留列名
+// headerColumn 指定哪一列（列名或从 1 开始的列序号）成为转置后的表头行，空表示第一列
+func Transpose(rows [][]string, headerColumn string) ([][]string, error) {
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	// 补齐为矩形
+	grid := make([][]string, len(rows))
+	for i, row := range rows {
+		grid[i] = make([]string, width)
+		copy(grid[i], row)
+	}
+	for i, cell := range grid[0] {
+		grid[0][i] = HeaderName(cell)
+	}
+
+	// 将指定列移到最前面，转置后它会成为表头行
+	if headerColumn != "" {
+		index, err := findColumn(grid[0], headerColumn)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range grid {
+			key := row[index]
+			copy(row[1:index+1], row[:index])
+			row[0] = key
+		}
+	}
+
+	result := make([][]string, width)
+	for i := range result {
+		result[i] = make([]string, len(grid))
+		for j, row := range grid {
+			result[i][j] = row[i]
+		}
+	}
+	return result, nil
+}
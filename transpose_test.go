@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestTranspose(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        [][]string
+		headerColumn string
+		expected     [][]string
+		wantErr      bool
+	}{
+		{
+			"键值表",
+			[][]string{
+				{"Field", "Value"},
+				{"Name", "Jane"},
+				{"Age", "30"},
+			},
+			"",
+			[][]string{
+				{"Field", "Name", "Age"},
+				{"Value", "Jane", "30"},
+			},
+			false,
+		},
+		{
+			"不规则行补齐",
+			[][]string{
+				{"a", "b", "c"},
+				{"1"},
+				{"2", "3"},
+			},
+			"",
+			[][]string{
+				{"a", "1", "2"},
+				{"b", "", "3"},
+				{"c", "", ""},
+			},
+			false,
+		},
+		{
+			"指定表头列",
+			[][]string{
+				{"id", "name", "age"},
+				{"1", "Jane", "30"},
+				{"2", "John", "25"},
+			},
+			"name",
+			[][]string{
+				{"name", "Jane", "John"},
+				{"id", "1", "2"},
+				{"age", "30", "25"},
+			},
+			false,
+		},
+		{
+			"按序号指定表头列",
+			[][]string{
+				{"id", "name"},
+				{"1", "Jane"},
+			},
+			"2",
+			[][]string{
+				{"name", "Jane"},
+				{"id", "1"},
+			},
+			false,
+		},
+		{
+			"去掉表头指令",
+			[][]string{
+				{"^c Field", "^r:num(2) v", "^hide note"},
+				{"x", "1", "a"},
+			},
+			"",
+			[][]string{
+				{"Field", "x"},
+				{"v", "1"},
+				{"note", "a"},
+			},
+			false,
+		},
+		{
+			"未知表头列",
+			[][]string{{"id"}, {"1"}},
+			"name",
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Transpose(tt.input, tt.headerColumn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Transpose() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !equalRows(result, tt.expected) {
+				t.Errorf("Transpose() = %v, 期望 %v", result, tt.expected)
+			}
+		})
+	}
+}
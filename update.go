@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// tableMarkerRegex matches an opening marker: <!-- table:src=data/limits.csv -->
+	tableMarkerRegex = regexp.MustCompile(`^\s*<!--\s*table:src=("[^"]*"|\S+?)\s*-->\s*$`)
+	// tableEndMarkerRegex matches a closing marker: <!-- /table -->
+	tableEndMarkerRegex = regexp.MustCompile(`^\s*<!--\s*/table\s*-->\s*$`)
+)
+
+// TableMarker Markdown 文档中由标记注释包围的表格区域
+type TableMarker struct {
+	Source    string // 表格数据来源文件
+	StartLine int    // 开始标记所在行（从 0 开始）
+	EndLine   int    // 结束标记所在行（从 0 开始）
+}
+
+// FindTableMarkers 查找文档中的所有表格标记区域，跳过围栏代码块
+// 区域以 <!-- table:src=文件 --> 开始，以 <!-- /table --> 结束，不能嵌套
+func FindTableMarkers(lines []string) ([]TableMarker, error) {
+	var markers []TableMarker
+	var open *TableMarker
+	fence := ""
+	for i, line := range lines {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if matches := fenceRegex.FindStringSubmatch(line); matches != nil {
+			fence = matches[1]
+			continue
+		}
+
+		if matches := tableMarkerRegex.FindStringSubmatch(line); matches != nil {
+			if open != nil {
+				return nil, fmt.Errorf("line %d: table marker opened before line %d was closed", i+1, open.StartLine+1)
+			}
+			source := strings.Trim(matches[1], `"`)
+			if source == "" {
+				return nil, fmt.Errorf("line %d: table marker has an empty src", i+1)
+			}
+			open = &TableMarker{Source: source, StartLine: i}
+			continue
+		}
+		if tableEndMarkerRegex.MatchString(line) {
+			if open == nil {
+				return nil, fmt.Errorf("line %d: closing table marker without an opening marker", i+1)
+			}
+			open.EndLine = i
+			markers = append(markers, *open)
+			open = nil
+		}
+	}
+	if open != nil {
+		return nil, fmt.Errorf("line %d: table marker is never closed", open.StartLine+1)
+	}
+	return markers, nil
+}
+
+// TableUpdate 重新生成的一个标记区域
+type TableUpdate struct {
+	TableMarker
+	Old []string // 标记之间原有的行（不含换行符）
+	New []string // 重新生成的表格行
+}
+
+// Stale 表格内容是否过期，只比较行内容，不比较换行符
+func (u TableUpdate) Stale() bool {
+	return !slices.Equal(u.Old, u.New)
+}
+
+// UpdateMarkedTables 重新生成文档中所有标记区域内的表格，只替换标记之间的内容
+// render 根据来源文件生成 Markdown 表格；区域外的内容（包括每行的换行符）保持不变，
+// 生成的表格行使用开始标记行的换行符。返回新文档和每个区域的更新结果
+func UpdateMarkedTables(doc string, render func(source string) (string, error)) (string, []TableUpdate, error) {
+	raw, lines := splitLinesKeepEnds(doc)
+	markers, err := FindTableMarkers(lines)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var result strings.Builder
+	var updates []TableUpdate
+	next := 0
+	for _, marker := range markers {
+		table, err := render(marker.Source)
+		if err != nil {
+			return "", nil, fmt.Errorf("line %d: %s: %v", marker.StartLine+1, marker.Source, err)
+		}
+		update := TableUpdate{TableMarker: marker, Old: lines[marker.StartLine+1 : marker.EndLine], New: strings.Split(table, "\n")}
+		updates = append(updates, update)
+
+		result.WriteString(strings.Join(raw[next:marker.StartLine+1], ""))
+		eol := lineEnding(raw[marker.StartLine])
+		for _, line := range update.New {
+			result.WriteString(line + eol)
+		}
+		next = marker.EndLine
+	}
+	result.WriteString(strings.Join(raw[next:], ""))
+	return result.String(), updates, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFindTableMarkers(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		expected []TableMarker
+		wantErr  bool
+	}{
+		{
+			"两个区域",
+			"# Limits\n<!-- table:src=data/limits.csv -->\n| old |\n<!-- /table -->\ntext\n<!--table:src=\"a b.tsv\"-->\n<!-- /table -->",
+			[]TableMarker{{"data/limits.csv", 1, 3}, {"a b.tsv", 5, 6}},
+			false,
+		},
+		{
+			"跳过代码块",
+			"```\n<!-- table:src=x.csv -->\n```\n<!-- table:src=y.csv -->\n<!-- /table -->",
+			[]TableMarker{{"y.csv", 3, 4}},
+			false,
+		},
+		{"没有结束标记", "<!-- table:src=x.csv -->\n| a |", nil, true},
+		{"多余的结束标记", "<!-- /table -->", nil, true},
+		{"嵌套标记", "<!-- table:src=x.csv -->\n<!-- table:src=y.csv -->\n<!-- /table -->", nil, true},
+		{"空来源", "<!-- table:src=\"\" -->\n<!-- /table -->", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FindTableMarkers(strings.Split(tt.doc, "\n"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindTableMarkers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && fmt.Sprint(result) != fmt.Sprint(tt.expected) {
+				t.Errorf("FindTableMarkers() = %v, 期望 %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateMarkedTables(t *testing.T) {
+	render := func(source string) (string, error) {
+		if source == "missing.csv" {
+			return "", fmt.Errorf("not found")
+		}
+		return "| " + source + " |\n|---|", nil
+	}
+
+	tests := []struct {
+		name     string
+		doc      string
+		expected string
+		count    int
+		wantErr  bool
+	}{
+		{
+			"替换区域内容",
+			"# Title\n<!-- table:src=a.csv -->\n| stale |\n|---|\n| row |\n<!-- /table -->\nfooter\n",
+			"# Title\n<!-- table:src=a.csv -->\n| a.csv |\n|---|\n<!-- /table -->\nfooter\n",
+			1,
+			false,
+		},
+		{
+			"空区域",
+			"<!-- table:src=a.csv -->\n<!-- /table -->",
+			"<!-- table:src=a.csv -->\n| a.csv |\n|---|\n<!-- /table -->",
+			1,
+			false,
+		},
+		{
+			"保留 CRLF 换行",
+			"intro\r\n<!-- table:src=b.csv -->\r\n<!-- /table -->\r\n",
+			"intro\r\n<!-- table:src=b.csv -->\r\n| b.csv |\r\n|---|\r\n<!-- /table -->\r\n",
+			1,
+			false,
+		},
+		{
+			"区域外的换行符保持不变",
+			"a\r\nb\nc\rd\u2028e\n<!-- table:src=c.csv -->\r\n| old |\n<!-- /table -->\nf\r\n",
+			"a\r\nb\nc\rd\u2028e\n<!-- table:src=c.csv -->\r\n| c.csv |\r\n|---|\r\n<!-- /table -->\nf\r\n",
+			1,
+			false,
+		},
+		{"没有标记", "plain text", "plain text", 0, false},
+		{"来源出错", "<!-- table:src=missing.csv -->\n<!-- /table -->", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, updates, err := UpdateMarkedTables(tt.doc, render)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateMarkedTables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("UpdateMarkedTables() = %q, 期望 %q", result, tt.expected)
+			}
+			if len(updates) != tt.count {
+				t.Errorf("UpdateMarkedTables() 更新 %d 个表格, 期望 %d", len(updates), tt.count)
+			}
+		})
+	}
+}
+
+func TestTableUpdateStale(t *testing.T) {
+	render := func(source string) (string, error) {
+		return "| " + source + " |\n|---|", nil
+	}
+	// 换行符混用但表格内容是最新的
+	doc := "# Title\r\n<!-- table:src=a.csv -->\n| a.csv |\r\n|---|\n<!-- /table -->\n" +
+		"<!-- table:src=b.csv -->\n| old |\n|---|\n<!-- /table -->\n"
+	_, updates, err := UpdateMarkedTables(doc, render)
+	if err != nil {
+		t.Fatalf("UpdateMarkedTables() error = %v", err)
+	}
+	if len(updates) != 2 || updates[0].Stale() || !updates[1].Stale() {
+		t.Errorf("UpdateMarkedTables() = %+v, 期望只有第二个表格过期", updates)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// watchBlockedFlags are conversion flags that choose other inputs or outputs, so they
+// cannot be used with watch, which always reads from and writes to the clipboard
+var watchBlockedFlags = map[string]bool{
+	"clipboard": true,
+	"copy":      true,
+	"outdir":    true,
+	"o":         true,
+	"append":    true,
+	"update":    true,
+	"check":     true,
+}
+
+// clipboardWatcher converts table data that appears on the clipboard and writes the
+// Markdown back. It remembers the last content it has seen, including its own output,
+// so each copy is converted once and the result is not converted again
+type clipboardWatcher struct {
+	read    func() (string, error)
+	write   func(string) error
+	convert func(string) (string, error)
+	last    string
+	// html also accepts HTML tables, read from the clipboard's text/html flavor with -html
+	html bool
+}
+
+// prime records the current clipboard content so that only later copies are converted
+func (w *clipboardWatcher) prime() error {
+	content, err := w.read()
+	if err != nil {
+		return err
+	}
+	w.last = strings.TrimRight(content, "\r\n")
+	return nil
+}
+
+// poll checks the clipboard once and returns the Markdown when new table data was
+// converted and written back
+func (w *clipboardWatcher) poll() (string, bool, error) {
+	content, err := w.read()
+	if err != nil {
+		return "", false, err
+	}
+	// Clipboard tools may add or drop a trailing newline, so compare without it
+	content = strings.TrimRight(content, "\r\n")
+	if content == w.last {
+		return "", false, nil
+	}
+	w.last = content
+	if !(w.html && isHTMLTable(content)) && !looksLikeTable(content) || strings.HasPrefix(strings.TrimSpace(content), "|") {
+		// Not table data, or already a Markdown table
+		return "", false, nil
+	}
+
+	markdown, err := w.convert(content)
+	if err != nil {
+		return "", false, err
+	}
+	if err := w.write(markdown); err != nil {
+		return "", false, err
+	}
+	w.last = markdown
+	return markdown, true, nil
+}
+
+// runWatch implements the watch subcommand: it polls the clipboard and replaces every
+// newly copied table with its Markdown version until interrupted
+func runWatch(args []string, lang string) {
+	flags, opts := newConvertFlags("watch", lang)
+	interval := flags.Duration("interval", 500*time.Millisecond, errorMsg(lang, "检查剪贴板的间隔", "How often to check the clipboard"))
+	setupUsage(flags, lang)
+	flags.Parse(args)
+
+	flags.Visit(func(f *flag.Flag) {
+		if watchBlockedFlags[f.Name] {
+			printErrorf(lang, "错误: watch 不支持 -%s", "Error: -%s cannot be used with watch", f.Name)
+		}
+	})
+	if *interval <= 0 {
+		printErrorf(lang, "错误: -interval 必须大于 0", "Error: -interval must be greater than 0")
+	}
+	exitOnError(lang, applyConfig(flags, opts.profile, watchBlockedFlags, lang))
+	// watch always reads from the clipboard
+	opts.fromClipboard = true
+	exitOnError(lang, validateOptions(*opts, lang))
+
+	watcher := &clipboardWatcher{
+		read: func() (string, error) {
+			return readFromClipboard(clipboardTypes(opts.html))
+		},
+		write: writeToClipboard,
+		html:  opts.html,
+		convert: func(content string) (string, error) {
+			input, err := DecodeInput([]byte(content), opts.encoding)
+			if err != nil {
+				return "", err
+			}
+			return convertTable(strings.TrimRight(input, "\r\n"), *opts, lang)
+		},
+	}
+	if err := watcher.prime(); err != nil {
+		printErrorf(lang, "无法从剪贴板读取: %v", "Failed to read from clipboard: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	fmt.Fprintln(os.Stderr, errorMsg(lang,
+		"正在监视剪贴板，复制表格后会自动转换为 Markdown，按 Ctrl+C 停止",
+		"Watching the clipboard, copied tables are converted to Markdown. Press Ctrl+C to stop"))
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(os.Stderr, errorMsg(lang, "已停止监视", "Stopped watching"))
+			return
+		case <-ticker.C:
+			markdown, converted, err := watcher.poll()
+			if err != nil {
+				// Keep watching: the next copy may be valid, and clipboard errors are often transient
+				fmt.Fprintf(os.Stderr, errorMsg(lang, "错误: %v", "Error: %v")+"\n", err)
+				continue
+			}
+			if converted {
+				fmt.Fprintln(os.Stderr, errorMsg(lang, "✓ Markdown 表格已复制到剪贴板", "✓ Markdown table copied to clipboard"))
+				fmt.Printf("%s\n\n", markdown)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeClipboard 模拟剪贴板，记录写入次数
+type fakeClipboard struct {
+	content string
+	writes  int
+}
+
+func (c *fakeClipboard) read() (string, error) {
+	return c.content, nil
+}
+
+func (c *fakeClipboard) write(data string) error {
+	// 模拟剪贴板工具在末尾追加换行
+	c.content = data + "\n"
+	c.writes++
+	return nil
+}
+
+// newTestWatcher 创建使用 fakeClipboard 的 clipboardWatcher
+func newTestWatcher(clipboard *fakeClipboard) *clipboardWatcher {
+	return &clipboardWatcher{
+		read:  clipboard.read,
+		write: clipboard.write,
+		convert: func(content string) (string, error) {
+			return convertTable(content, options{style: "compact"}, "en")
+		},
+	}
+}
+
+func TestClipboardWatcher(t *testing.T) {
+	clipboard := &fakeClipboard{content: "a\tb\n1\t2\n"}
+	watcher := newTestWatcher(clipboard)
+	if err := watcher.prime(); err != nil {
+		t.Fatalf("prime() error = %v", err)
+	}
+
+	steps := []struct {
+		name      string
+		copy      string
+		converted bool
+		expected  string
+	}{
+		{"启动前的内容不转换", "", false, ""},
+		{"新复制的表格", "x\ty\n3\t4", true, "|x|y|\n|---|---|\n|3|4|"},
+		{"忽略自己写入的结果", "", false, ""},
+		{"普通文本", "hello", false, ""},
+		{"已经是 Markdown 表格", "| a  | b  |\n|----|----|", false, ""},
+		{"再次复制相同的表格", "x\ty\n3\t4\r\n", true, "|x|y|\n|---|---|\n|3|4|"},
+	}
+
+	for _, step := range steps {
+		if step.copy != "" {
+			clipboard.content = step.copy
+		}
+		markdown, converted, err := watcher.poll()
+		if err != nil {
+			t.Fatalf("%s: poll() error = %v", step.name, err)
+		}
+		if converted != step.converted || markdown != step.expected {
+			t.Errorf("%s: poll() = %q, %v, 期望 %q, %v", step.name, markdown, converted, step.expected, step.converted)
+		}
+	}
+	if clipboard.writes != 2 {
+		t.Errorf("写入剪贴板 %d 次, 期望 2 次", clipboard.writes)
+	}
+}
+
+func TestClipboardWatcherHTML(t *testing.T) {
+	table := "<table><tr><td>a</td><td>b</td></tr><tr><td>1</td><td>2</td></tr></table>"
+	tests := []struct {
+		name      string
+		html      bool
+		converted bool
+	}{
+		{"使用 -html 时转换 HTML 表格", true, true},
+		{"没有 -html 时忽略 HTML", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clipboard := &fakeClipboard{}
+			watcher := newTestWatcher(clipboard)
+			watcher.html = tt.html
+			watcher.convert = func(content string) (string, error) {
+				return convertTable(content, options{style: "compact", fromClipboard: true, html: true}, "en")
+			}
+			clipboard.content = table
+			markdown, converted, err := watcher.poll()
+			if err != nil {
+				t.Fatalf("poll() error = %v", err)
+			}
+			if converted != tt.converted {
+				t.Errorf("poll() = %q, %v, 期望 %v", markdown, converted, tt.converted)
+			}
+		})
+	}
+}
+
+func TestClipboardWatcherConvertError(t *testing.T) {
+	clipboard := &fakeClipboard{}
+	watcher := newTestWatcher(clipboard)
+	watcher.convert = func(string) (string, error) {
+		return "", errors.New("bad table")
+	}
+
+	clipboard.content = "a,b\n1,2"
+	if _, _, err := watcher.poll(); err == nil {
+		t.Fatal("poll() 期望返回转换错误")
+	}
+	// 同一内容只报告一次错误
+	if _, converted, err := watcher.poll(); err != nil || converted {
+		t.Errorf("poll() = %v, %v, 期望忽略已处理的内容", converted, err)
+	}
+	if clipboard.writes != 0 {
+		t.Errorf("写入剪贴板 %d 次, 期望 0 次", clipboard.writes)
+	}
+}
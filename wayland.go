@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// clipboardTextTypes 读取剪贴板时请求的纯文本 MIME 类型，按优先级排列
+var clipboardTextTypes = []string{"text/plain;charset=utf-8", "text/plain", "UTF8_STRING", "STRING", "TEXT"}
+
+// clipboardTypes 返回读取剪贴板时请求的类型；preferHTML 为 true 时优先请求 "text/html"，
+// 剪贴板没有 HTML 内容时仍然读取纯文本
+func clipboardTypes(preferHTML bool) []string {
+	if preferHTML {
+		return append([]string{"text/html"}, clipboardTextTypes...)
+	}
+	return clipboardTextTypes
+}
+
+// isWayland 检查是否运行在 Wayland 会话中
+func isWayland() bool {
+	return os.Getenv("WAYLAND_DISPLAY") != ""
+}
+
+// parseClipboardTypes 解析 wl-paste --list-types 的输出，每行一个 MIME 类型
+func parseClipboardTypes(output string) []string {
+	var types []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			types = append(types, line)
+		}
+	}
+	return types
+}
+
+// chooseClipboardType 返回 preferred 中第一个剪贴板提供的类型，都没有时返回空字符串
+// 比较时忽略大小写和 ";" 前后的空格（text/plain; charset=UTF-8 等同于 text/plain;charset=utf-8）
+func chooseClipboardType(offered, preferred []string) string {
+	normalize := func(mimeType string) string {
+		return strings.ToLower(strings.ReplaceAll(mimeType, " ", ""))
+	}
+	for _, want := range preferred {
+		for _, mimeType := range offered {
+			if normalize(mimeType) == normalize(want) {
+				return mimeType
+			}
+		}
+	}
+	return ""
+}
+
+// getWaylandClipboardCommand 返回 wl-clipboard 的命令
+// 读取时先用 wl-paste --list-types 查询剪贴板提供的类型，再按 mimeTypes 的顺序选择；
+// 查询失败（例如剪贴板为空）或没有匹配的类型时交给 wl-paste 自行选择文本类型
+func getWaylandClipboardCommand(read bool, mimeTypes []string) (*exec.Cmd, error) {
+	if !read {
+		return exec.Command("wl-copy", "--type", "text/plain;charset=utf-8"), nil
+	}
+
+	output, err := exec.Command("wl-paste", "--list-types").Output()
+	if err == nil {
+		if mimeType := chooseClipboardType(parseClipboardTypes(string(output)), mimeTypes); mimeType != "" {
+			return exec.Command("wl-paste", "--no-newline", "--type", mimeType), nil
+		}
+	}
+	return exec.Command("wl-paste", "--no-newline"), nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseClipboardTypes(t *testing.T) {
+	types := parseClipboardTypes("text/html\ntext/plain;charset=utf-8\n\nUTF8_STRING\n")
+	expected := []string{"text/html", "text/plain;charset=utf-8", "UTF8_STRING"}
+	if strings.Join(types, ",") != strings.Join(expected, ",") {
+		t.Errorf("parseClipboardTypes() = %v, 期望 %v", types, expected)
+	}
+}
+
+func TestChooseClipboardType(t *testing.T) {
+	tests := []struct {
+		name      string
+		offered   []string
+		preferred []string
+		expected  string
+	}{
+		{"优先 UTF-8 纯文本", []string{"text/html", "text/plain", "text/plain;charset=utf-8"}, clipboardTextTypes, "text/plain;charset=utf-8"},
+		{"按优先级回退", []string{"text/html", "UTF8_STRING"}, clipboardTextTypes, "UTF8_STRING"},
+		{"忽略大小写和空格", []string{"text/plain; charset=UTF-8"}, clipboardTextTypes, "text/plain; charset=UTF-8"},
+		{"请求 HTML", []string{"text/plain", "text/html"}, clipboardTypes(true), "text/html"},
+		{"没有 HTML 时使用纯文本", []string{"text/plain"}, clipboardTypes(true), "text/plain"},
+		{"没有匹配的类型", []string{"image/png"}, clipboardTextTypes, ""},
+		{"不请求 HTML 时忽略 HTML", []string{"text/html", "STRING"}, clipboardTypes(false), "STRING"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := chooseClipboardType(tt.offered, tt.preferred); result != tt.expected {
+				t.Errorf("chooseClipboardType() = %q, 期望 %q", result, tt.expected)
+			}
+		})
+	}
+}
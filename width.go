@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// wrapSeparator 单元格内换行使用的标记
+const wrapSeparator = "<br>"
+
+// ParseColumnWidths 解析按列指定的最大宽度，格式: "列名:宽度,列名:宽度"
+func ParseColumnWidths(spec string) (map[string]int, error) {
+	widths := make(map[string]int)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		sep := strings.LastIndex(item, ":")
+		if sep <= 0 {
+			return nil, fmt.Errorf("invalid column width %q, expected column:width", item)
+		}
+		width, err := strconv.Atoi(strings.TrimSpace(item[sep+1:]))
+		if err != nil || width <= 0 {
+			return nil, fmt.Errorf("invalid column width %q, width must be a positive integer", item)
+		}
+		widths[strings.TrimSpace(item[:sep])] = width
+	}
+	return widths, nil
+}
+
+// columnMaxWidth 返回列的最大显示宽度
+// 优先级：命令行按列指定 > 表头指令 > 全局最大宽度
+func (c *Converter) columnMaxWidth(name string, columnIndex int, headerWidth int) int {
+	if width, ok := c.ColumnMaxWidths[name]; ok {
+		return width
+	}
+	if width, ok := c.ColumnMaxWidths[strconv.Itoa(columnIndex+1)]; ok {
+		return width
+	}
+	if headerWidth > 0 {
+		return headerWidth
+	}
+	return c.MaxWidth
+}
+
+// applyMaxWidth 截断或换行指定列中超过最大显示宽度的单元格
+func (c *Converter) applyMaxWidth(rows [][]string, columnIndex int, maxWidth int) {
+	for _, row := range rows {
+		if columnIndex >= len(row) {
+			continue
+		}
+		if c.Wrap {
+			row[columnIndex] = c.wrapToWidth(row[columnIndex], maxWidth)
+		} else {
+			row[columnIndex] = c.truncateToWidth(row[columnIndex], maxWidth)
+		}
+	}
+}
+
+// cellWidth 返回单元格占用的显示宽度
+// 使用 -wrap 时单元格按 <br> 分多行显示，取最宽一行的宽度，换行后的列不会比最大宽度更宽
+func (c *Converter) cellWidth(cell string) int {
+	if !c.Wrap {
+		return c.DisplayWidth(cell)
+	}
+	width := 0
+	for _, line := range strings.Split(cell, wrapSeparator) {
+		width = max(width, c.DisplayWidth(line))
+	}
+	return width
+}
+
+// truncateToWidth 按显示宽度截断字符串并添加省略号，不会拆开全角字符或字素簇
+func (c *Converter) truncateToWidth(s string, maxWidth int) string {
+	if c.DisplayWidth(s) <= maxWidth {
+		return s
+	}
+
+	const ellipsis = "…"
+	head, _ := c.splitAtWidth(s, maxWidth-c.DisplayWidth(ellipsis))
+	return head + ellipsis
+}
+
+// wrapToWidth 按显示宽度软换行，优先在空格处断行，行之间用 <br> 连接
+// 没有空格的长文本（例如中文）按字符边界断行
+func (c *Converter) wrapToWidth(s string, maxWidth int) string {
+	if c.DisplayWidth(s) <= maxWidth {
+		return s
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if c.DisplayWidth(candidate) <= maxWidth {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		// 单个词超宽时强制断开
+		for c.DisplayWidth(word) > maxWidth {
+			head, rest := c.splitAtWidth(word, maxWidth)
+			if head == "" {
+				// 单个字素簇就超过最大宽度，只能整个放在一行
+				head, rest, _, _ = uniseg.FirstGraphemeClusterInString(word, -1)
+			}
+			lines = append(lines, head)
+			word = rest
+		}
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, wrapSeparator)
+}
+
+// splitAtWidth 在不超过 width 显示宽度的最后一个字素簇边界处拆分字符串
+func (c *Converter) splitAtWidth(s string, width int) (string, string) {
+	used := 0
+	graphemes := uniseg.NewGraphemes(s)
+	for graphemes.Next() {
+		clusterWidth := c.clusterWidth(graphemes.Runes())
+		if used+clusterWidth > width {
+			start, _ := graphemes.Positions()
+			return s[:start], s[start:]
+		}
+		used += clusterWidth
+	}
+	return s, ""
+}
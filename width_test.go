@@ -0,0 +1,132 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseColumnWidths(t *testing.T) {
+	widths, err := ParseColumnWidths("desc:40, 3:10")
+	if err != nil {
+		t.Fatalf("ParseColumnWidths() error = %v", err)
+	}
+	if widths["desc"] != 40 || widths["3"] != 10 {
+		t.Errorf("ParseColumnWidths() = %v", widths)
+	}
+
+	for _, spec := range []string{"desc", "desc:0", "desc:wide"} {
+		if _, err := ParseColumnWidths(spec); err == nil {
+			t.Errorf("ParseColumnWidths(%q) 应返回错误", spec)
+		}
+	}
+}
+
+func TestTruncateToWidth(t *testing.T) {
+	converter := NewConverter()
+
+	tests := []struct {
+		name     string
+		input    string
+		maxWidth int
+		expected string
+	}{
+		{"未超宽", "Hello", 5, "Hello"},
+		{"英文截断", "Hello world", 6, "Hello…"},
+		{"中文不拆字", "你好世界", 6, "你好…"},
+		{"中文奇数宽度", "你好世界", 4, "你…"},
+		{"不拆开组合 emoji", "👨\u200d👩\u200d👧👍🏽abc", 4, "👨\u200d👩\u200d👧…"},
+		{"不拆开组合重音", "ée\u0301e\u0301e\u0301", 3, "ée\u0301…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := converter.truncateToWidth(tt.input, tt.maxWidth)
+			if result != tt.expected {
+				t.Errorf("truncateToWidth(%q, %d) = %q, 期望 %q", tt.input, tt.maxWidth, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWrapToWidth(t *testing.T) {
+	converter := NewConverter()
+
+	tests := []struct {
+		name     string
+		input    string
+		maxWidth int
+		expected string
+	}{
+		{"未超宽", "Hello", 5, "Hello"},
+		{"按单词换行", "The quick brown fox", 10, "The quick<br>brown fox"},
+		{"长单词强制断开", "abcdefghij", 4, "abcd<br>efgh<br>ij"},
+		{"中文按字符断行", "你好世界你好", 5, "你好<br>世界<br>你好"},
+		{"中英混合", "Hello 世界", 6, "Hello<br>世界"},
+		{"宽度小于单个全角字符", "你好", 1, "你<br>好"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := converter.wrapToWidth(tt.input, tt.maxWidth)
+			if result != tt.expected {
+				t.Errorf("wrapToWidth(%q, %d) = %q, 期望 %q", tt.input, tt.maxWidth, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertToMarkdownWithMaxWidth(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxWidth     int
+		columnWidths map[string]int
+		wrap         bool
+		expected     string
+	}{
+		{
+			"全局截断",
+			6,
+			nil,
+			false,
+			"| id  | notes   |\n|-----|---------|\n| 1   | hello…  |",
+		},
+		{
+			"按列宽度优先",
+			6,
+			map[string]int{"notes": 8},
+			false,
+			"| id  | notes     |\n|-----|-----------|\n| 1   | hello w…  |",
+		},
+		{
+			"换行",
+			6,
+			nil,
+			true,
+			"| id  | notes  |\n|-----|--------|\n| 1   | hello<br>world  |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter := NewConverter()
+			converter.MaxWidth = tt.maxWidth
+			converter.ColumnMaxWidths = tt.columnWidths
+			converter.Wrap = tt.wrap
+
+			result := converter.ConvertToMarkdown([][]string{{"id", "notes"}, {"1", "hello world"}})
+			if result != tt.expected {
+				t.Errorf("ConvertToMarkdown() = %q, 期望 %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertToMarkdownLimitsRenamedHeader(t *testing.T) {
+	converter := NewConverter()
+	converter.MaxWidth = 6
+
+	result := converter.ConvertToMarkdown([][]string{{"^as(Long Name) n", "^as(Renamed) m"}, {"1", "2"}})
+	expected := "| Long …  | Renam…  |\n|---------|---------|\n| 1       | 2       |"
+	if result != expected {
+		t.Errorf("ConvertToMarkdown() = %q, 期望 %q", result, expected)
+	}
+}